@@ -23,6 +23,11 @@ const _ = grpc.SupportPackageIsVersion7
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type WatchServiceClient interface {
 	Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (WatchService_WatchClient, error)
+	WatchMany(ctx context.Context, in *WatchManyRequest, opts ...grpc.CallOption) (WatchService_WatchManyClient, error)
+	WatchStream(ctx context.Context, opts ...grpc.CallOption) (WatchService_WatchStreamClient, error)
+	Ack(ctx context.Context, in *AckRequest, opts ...grpc.CallOption) (*AckResponse, error)
+	List(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListResponse, error)
+	Get(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*GetResponse, error)
 }
 
 type watchServiceClient struct {
@@ -65,11 +70,106 @@ func (x *watchServiceWatchClient) Recv() (*WatchResponse, error) {
 	return m, nil
 }
 
+func (c *watchServiceClient) WatchMany(ctx context.Context, in *WatchManyRequest, opts ...grpc.CallOption) (WatchService_WatchManyClient, error) {
+	stream, err := c.cc.NewStream(ctx, &WatchService_ServiceDesc.Streams[1], "/api.WatchService/WatchMany", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &watchServiceWatchManyClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type WatchService_WatchManyClient interface {
+	Recv() (*WatchResponse, error)
+	grpc.ClientStream
+}
+
+type watchServiceWatchManyClient struct {
+	grpc.ClientStream
+}
+
+func (x *watchServiceWatchManyClient) Recv() (*WatchResponse, error) {
+	m := new(WatchResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *watchServiceClient) WatchStream(ctx context.Context, opts ...grpc.CallOption) (WatchService_WatchStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &WatchService_ServiceDesc.Streams[2], "/api.WatchService/WatchStream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &watchServiceWatchStreamClient{stream}
+	return x, nil
+}
+
+type WatchService_WatchStreamClient interface {
+	Send(*WatchStreamRequest) error
+	Recv() (*WatchResponse, error)
+	grpc.ClientStream
+}
+
+type watchServiceWatchStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *watchServiceWatchStreamClient) Send(m *WatchStreamRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *watchServiceWatchStreamClient) Recv() (*WatchResponse, error) {
+	m := new(WatchResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *watchServiceClient) Ack(ctx context.Context, in *AckRequest, opts ...grpc.CallOption) (*AckResponse, error) {
+	out := new(AckResponse)
+	err := c.cc.Invoke(ctx, "/api.WatchService/Ack", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *watchServiceClient) List(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListResponse, error) {
+	out := new(ListResponse)
+	err := c.cc.Invoke(ctx, "/api.WatchService/List", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *watchServiceClient) Get(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*GetResponse, error) {
+	out := new(GetResponse)
+	err := c.cc.Invoke(ctx, "/api.WatchService/Get", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // WatchServiceServer is the server API for WatchService service.
 // All implementations must embed UnimplementedWatchServiceServer
 // for forward compatibility
 type WatchServiceServer interface {
 	Watch(*WatchRequest, WatchService_WatchServer) error
+	WatchMany(*WatchManyRequest, WatchService_WatchManyServer) error
+	WatchStream(WatchService_WatchStreamServer) error
+	Ack(context.Context, *AckRequest) (*AckResponse, error)
+	List(context.Context, *ListRequest) (*ListResponse, error)
+	Get(context.Context, *GetRequest) (*GetResponse, error)
 	mustEmbedUnimplementedWatchServiceServer()
 }
 
@@ -80,6 +180,21 @@ type UnimplementedWatchServiceServer struct {
 func (UnimplementedWatchServiceServer) Watch(*WatchRequest, WatchService_WatchServer) error {
 	return status.Errorf(codes.Unimplemented, "method Watch not implemented")
 }
+func (UnimplementedWatchServiceServer) WatchMany(*WatchManyRequest, WatchService_WatchManyServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchMany not implemented")
+}
+func (UnimplementedWatchServiceServer) WatchStream(WatchService_WatchStreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchStream not implemented")
+}
+func (UnimplementedWatchServiceServer) Ack(context.Context, *AckRequest) (*AckResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Ack not implemented")
+}
+func (UnimplementedWatchServiceServer) List(context.Context, *ListRequest) (*ListResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method List not implemented")
+}
+func (UnimplementedWatchServiceServer) Get(context.Context, *GetRequest) (*GetResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Get not implemented")
+}
 func (UnimplementedWatchServiceServer) mustEmbedUnimplementedWatchServiceServer() {}
 
 // UnsafeWatchServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -114,19 +229,144 @@ func (x *watchServiceWatchServer) Send(m *WatchResponse) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _WatchService_WatchMany_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchManyRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(WatchServiceServer).WatchMany(m, &watchServiceWatchManyServer{stream})
+}
+
+type WatchService_WatchManyServer interface {
+	Send(*WatchResponse) error
+	grpc.ServerStream
+}
+
+type watchServiceWatchManyServer struct {
+	grpc.ServerStream
+}
+
+func (x *watchServiceWatchManyServer) Send(m *WatchResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _WatchService_WatchStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(WatchServiceServer).WatchStream(&watchServiceWatchStreamServer{stream})
+}
+
+type WatchService_WatchStreamServer interface {
+	Send(*WatchResponse) error
+	Recv() (*WatchStreamRequest, error)
+	grpc.ServerStream
+}
+
+type watchServiceWatchStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *watchServiceWatchStreamServer) Send(m *WatchResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *watchServiceWatchStreamServer) Recv() (*WatchStreamRequest, error) {
+	m := new(WatchStreamRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _WatchService_Ack_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AckRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WatchServiceServer).Ack(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.WatchService/Ack",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WatchServiceServer).Ack(ctx, req.(*AckRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WatchService_List_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WatchServiceServer).List(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.WatchService/List",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WatchServiceServer).List(ctx, req.(*ListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WatchService_Get_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WatchServiceServer).Get(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.WatchService/Get",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WatchServiceServer).Get(ctx, req.(*GetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // WatchService_ServiceDesc is the grpc.ServiceDesc for WatchService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
 var WatchService_ServiceDesc = grpc.ServiceDesc{
 	ServiceName: "api.WatchService",
 	HandlerType: (*WatchServiceServer)(nil),
-	Methods:     []grpc.MethodDesc{},
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Ack",
+			Handler:    _WatchService_Ack_Handler,
+		},
+		{
+			MethodName: "List",
+			Handler:    _WatchService_List_Handler,
+		},
+		{
+			MethodName: "Get",
+			Handler:    _WatchService_Get_Handler,
+		},
+	},
 	Streams: []grpc.StreamDesc{
 		{
 			StreamName:    "Watch",
 			Handler:       _WatchService_Watch_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "WatchMany",
+			Handler:       _WatchService_WatchMany_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "WatchStream",
+			Handler:       _WatchService_WatchStream_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
 	},
 	Metadata: "api/apiv1.proto",
 }
@@ -5,6 +5,7 @@ import (
 	"log"
 	"log/slog"
 	"os"
+	"time"
 
 	"github.com/cmwylie19/watch-informer/pkg/logging"
 	"github.com/cmwylie19/watch-informer/pkg/server"
@@ -17,6 +18,7 @@ import (
 
 var logLevel string
 var useInClusterConfig bool
+var resyncPeriod time.Duration
 
 var (
 	getInClusterConfig     = rest.InClusterConfig
@@ -69,13 +71,14 @@ var rootCmd = &cobra.Command{
 			logger.SetLevel(slog.LevelInfo) // Default to INFO level
 		}
 
-		server.StartGRPCServer(":50051", dynamicClient, config, logger)
+		server.StartGRPCServer(":50051", dynamicClient, config, resyncPeriod, logger)
 	},
 }
 
 func init() {
 	rootCmd.PersistentFlags().StringVarP(&logLevel, "log-level", "l", "info", "Log level (debug, info, error)")
 	rootCmd.PersistentFlags().BoolVar(&useInClusterConfig, "in-cluster", true, "Use in-cluster configuration")
+	rootCmd.PersistentFlags().DurationVar(&resyncPeriod, "resync-period", server.DefaultResyncPeriod, "How often informers resync for watches with include_resyncs, 0 disables resyncs")
 }
 
 func Execute() {
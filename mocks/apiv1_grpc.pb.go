@@ -37,6 +37,66 @@ func (m *MockWatchServiceClient) EXPECT() *MockWatchServiceClientMockRecorder {
 	return m.recorder
 }
 
+// Ack mocks base method.
+func (m *MockWatchServiceClient) Ack(ctx context.Context, in *api.AckRequest, opts ...grpc.CallOption) (*api.AckResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Ack", varargs...)
+	ret0, _ := ret[0].(*api.AckResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Ack indicates an expected call of Ack.
+func (mr *MockWatchServiceClientMockRecorder) Ack(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ack", reflect.TypeOf((*MockWatchServiceClient)(nil).Ack), varargs...)
+}
+
+// Get mocks base method.
+func (m *MockWatchServiceClient) Get(ctx context.Context, in *api.GetRequest, opts ...grpc.CallOption) (*api.GetResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Get", varargs...)
+	ret0, _ := ret[0].(*api.GetResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockWatchServiceClientMockRecorder) Get(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockWatchServiceClient)(nil).Get), varargs...)
+}
+
+// List mocks base method.
+func (m *MockWatchServiceClient) List(ctx context.Context, in *api.ListRequest, opts ...grpc.CallOption) (*api.ListResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "List", varargs...)
+	ret0, _ := ret[0].(*api.ListResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockWatchServiceClientMockRecorder) List(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockWatchServiceClient)(nil).List), varargs...)
+}
+
 // Watch mocks base method.
 func (m *MockWatchServiceClient) Watch(ctx context.Context, in *api.WatchRequest, opts ...grpc.CallOption) (api.WatchService_WatchClient, error) {
 	m.ctrl.T.Helper()
@@ -57,6 +117,46 @@ func (mr *MockWatchServiceClientMockRecorder) Watch(ctx, in interface{}, opts ..
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Watch", reflect.TypeOf((*MockWatchServiceClient)(nil).Watch), varargs...)
 }
 
+// WatchMany mocks base method.
+func (m *MockWatchServiceClient) WatchMany(ctx context.Context, in *api.WatchManyRequest, opts ...grpc.CallOption) (api.WatchService_WatchManyClient, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "WatchMany", varargs...)
+	ret0, _ := ret[0].(api.WatchService_WatchManyClient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WatchMany indicates an expected call of WatchMany.
+func (mr *MockWatchServiceClientMockRecorder) WatchMany(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WatchMany", reflect.TypeOf((*MockWatchServiceClient)(nil).WatchMany), varargs...)
+}
+
+// WatchStream mocks base method.
+func (m *MockWatchServiceClient) WatchStream(ctx context.Context, opts ...grpc.CallOption) (api.WatchService_WatchStreamClient, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "WatchStream", varargs...)
+	ret0, _ := ret[0].(api.WatchService_WatchStreamClient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WatchStream indicates an expected call of WatchStream.
+func (mr *MockWatchServiceClientMockRecorder) WatchStream(ctx interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WatchStream", reflect.TypeOf((*MockWatchServiceClient)(nil).WatchStream), varargs...)
+}
+
 // MockWatchService_WatchClient is a mock of WatchService_WatchClient interface.
 type MockWatchService_WatchClient struct {
 	ctrl     *gomock.Controller
@@ -180,6 +280,266 @@ func (mr *MockWatchService_WatchClientMockRecorder) Trailer() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Trailer", reflect.TypeOf((*MockWatchService_WatchClient)(nil).Trailer))
 }
 
+// MockWatchService_WatchManyClient is a mock of WatchService_WatchManyClient interface.
+type MockWatchService_WatchManyClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockWatchService_WatchManyClientMockRecorder
+}
+
+// MockWatchService_WatchManyClientMockRecorder is the mock recorder for MockWatchService_WatchManyClient.
+type MockWatchService_WatchManyClientMockRecorder struct {
+	mock *MockWatchService_WatchManyClient
+}
+
+// NewMockWatchService_WatchManyClient creates a new mock instance.
+func NewMockWatchService_WatchManyClient(ctrl *gomock.Controller) *MockWatchService_WatchManyClient {
+	mock := &MockWatchService_WatchManyClient{ctrl: ctrl}
+	mock.recorder = &MockWatchService_WatchManyClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWatchService_WatchManyClient) EXPECT() *MockWatchService_WatchManyClientMockRecorder {
+	return m.recorder
+}
+
+// CloseSend mocks base method.
+func (m *MockWatchService_WatchManyClient) CloseSend() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseSend")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CloseSend indicates an expected call of CloseSend.
+func (mr *MockWatchService_WatchManyClientMockRecorder) CloseSend() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseSend", reflect.TypeOf((*MockWatchService_WatchManyClient)(nil).CloseSend))
+}
+
+// Context mocks base method.
+func (m *MockWatchService_WatchManyClient) Context() context.Context {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Context")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// Context indicates an expected call of Context.
+func (mr *MockWatchService_WatchManyClientMockRecorder) Context() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockWatchService_WatchManyClient)(nil).Context))
+}
+
+// Header mocks base method.
+func (m *MockWatchService_WatchManyClient) Header() (metadata.MD, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Header")
+	ret0, _ := ret[0].(metadata.MD)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Header indicates an expected call of Header.
+func (mr *MockWatchService_WatchManyClientMockRecorder) Header() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Header", reflect.TypeOf((*MockWatchService_WatchManyClient)(nil).Header))
+}
+
+// Recv mocks base method.
+func (m *MockWatchService_WatchManyClient) Recv() (*api.WatchResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Recv")
+	ret0, _ := ret[0].(*api.WatchResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Recv indicates an expected call of Recv.
+func (mr *MockWatchService_WatchManyClientMockRecorder) Recv() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Recv", reflect.TypeOf((*MockWatchService_WatchManyClient)(nil).Recv))
+}
+
+// RecvMsg mocks base method.
+func (m_2 *MockWatchService_WatchManyClient) RecvMsg(m any) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "RecvMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecvMsg indicates an expected call of RecvMsg.
+func (mr *MockWatchService_WatchManyClientMockRecorder) RecvMsg(m interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecvMsg", reflect.TypeOf((*MockWatchService_WatchManyClient)(nil).RecvMsg), m)
+}
+
+// SendMsg mocks base method.
+func (m_2 *MockWatchService_WatchManyClient) SendMsg(m any) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "SendMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendMsg indicates an expected call of SendMsg.
+func (mr *MockWatchService_WatchManyClientMockRecorder) SendMsg(m interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMsg", reflect.TypeOf((*MockWatchService_WatchManyClient)(nil).SendMsg), m)
+}
+
+// Trailer mocks base method.
+func (m *MockWatchService_WatchManyClient) Trailer() metadata.MD {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Trailer")
+	ret0, _ := ret[0].(metadata.MD)
+	return ret0
+}
+
+// Trailer indicates an expected call of Trailer.
+func (mr *MockWatchService_WatchManyClientMockRecorder) Trailer() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Trailer", reflect.TypeOf((*MockWatchService_WatchManyClient)(nil).Trailer))
+}
+
+// MockWatchService_WatchStreamClient is a mock of WatchService_WatchStreamClient interface.
+type MockWatchService_WatchStreamClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockWatchService_WatchStreamClientMockRecorder
+}
+
+// MockWatchService_WatchStreamClientMockRecorder is the mock recorder for MockWatchService_WatchStreamClient.
+type MockWatchService_WatchStreamClientMockRecorder struct {
+	mock *MockWatchService_WatchStreamClient
+}
+
+// NewMockWatchService_WatchStreamClient creates a new mock instance.
+func NewMockWatchService_WatchStreamClient(ctrl *gomock.Controller) *MockWatchService_WatchStreamClient {
+	mock := &MockWatchService_WatchStreamClient{ctrl: ctrl}
+	mock.recorder = &MockWatchService_WatchStreamClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWatchService_WatchStreamClient) EXPECT() *MockWatchService_WatchStreamClientMockRecorder {
+	return m.recorder
+}
+
+// CloseSend mocks base method.
+func (m *MockWatchService_WatchStreamClient) CloseSend() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseSend")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CloseSend indicates an expected call of CloseSend.
+func (mr *MockWatchService_WatchStreamClientMockRecorder) CloseSend() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseSend", reflect.TypeOf((*MockWatchService_WatchStreamClient)(nil).CloseSend))
+}
+
+// Context mocks base method.
+func (m *MockWatchService_WatchStreamClient) Context() context.Context {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Context")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// Context indicates an expected call of Context.
+func (mr *MockWatchService_WatchStreamClientMockRecorder) Context() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockWatchService_WatchStreamClient)(nil).Context))
+}
+
+// Header mocks base method.
+func (m *MockWatchService_WatchStreamClient) Header() (metadata.MD, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Header")
+	ret0, _ := ret[0].(metadata.MD)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Header indicates an expected call of Header.
+func (mr *MockWatchService_WatchStreamClientMockRecorder) Header() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Header", reflect.TypeOf((*MockWatchService_WatchStreamClient)(nil).Header))
+}
+
+// Recv mocks base method.
+func (m *MockWatchService_WatchStreamClient) Recv() (*api.WatchResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Recv")
+	ret0, _ := ret[0].(*api.WatchResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Recv indicates an expected call of Recv.
+func (mr *MockWatchService_WatchStreamClientMockRecorder) Recv() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Recv", reflect.TypeOf((*MockWatchService_WatchStreamClient)(nil).Recv))
+}
+
+// RecvMsg mocks base method.
+func (m_2 *MockWatchService_WatchStreamClient) RecvMsg(m any) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "RecvMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecvMsg indicates an expected call of RecvMsg.
+func (mr *MockWatchService_WatchStreamClientMockRecorder) RecvMsg(m interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecvMsg", reflect.TypeOf((*MockWatchService_WatchStreamClient)(nil).RecvMsg), m)
+}
+
+// Send mocks base method.
+func (m *MockWatchService_WatchStreamClient) Send(arg0 *api.WatchStreamRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Send", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Send indicates an expected call of Send.
+func (mr *MockWatchService_WatchStreamClientMockRecorder) Send(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockWatchService_WatchStreamClient)(nil).Send), arg0)
+}
+
+// SendMsg mocks base method.
+func (m_2 *MockWatchService_WatchStreamClient) SendMsg(m any) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "SendMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendMsg indicates an expected call of SendMsg.
+func (mr *MockWatchService_WatchStreamClientMockRecorder) SendMsg(m interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMsg", reflect.TypeOf((*MockWatchService_WatchStreamClient)(nil).SendMsg), m)
+}
+
+// Trailer mocks base method.
+func (m *MockWatchService_WatchStreamClient) Trailer() metadata.MD {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Trailer")
+	ret0, _ := ret[0].(metadata.MD)
+	return ret0
+}
+
+// Trailer indicates an expected call of Trailer.
+func (mr *MockWatchService_WatchStreamClientMockRecorder) Trailer() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Trailer", reflect.TypeOf((*MockWatchService_WatchStreamClient)(nil).Trailer))
+}
+
 // MockWatchServiceServer is a mock of WatchServiceServer interface.
 type MockWatchServiceServer struct {
 	ctrl     *gomock.Controller
@@ -203,6 +563,51 @@ func (m *MockWatchServiceServer) EXPECT() *MockWatchServiceServerMockRecorder {
 	return m.recorder
 }
 
+// Ack mocks base method.
+func (m *MockWatchServiceServer) Ack(arg0 context.Context, arg1 *api.AckRequest) (*api.AckResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Ack", arg0, arg1)
+	ret0, _ := ret[0].(*api.AckResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Ack indicates an expected call of Ack.
+func (mr *MockWatchServiceServerMockRecorder) Ack(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ack", reflect.TypeOf((*MockWatchServiceServer)(nil).Ack), arg0, arg1)
+}
+
+// Get mocks base method.
+func (m *MockWatchServiceServer) Get(arg0 context.Context, arg1 *api.GetRequest) (*api.GetResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", arg0, arg1)
+	ret0, _ := ret[0].(*api.GetResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockWatchServiceServerMockRecorder) Get(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockWatchServiceServer)(nil).Get), arg0, arg1)
+}
+
+// List mocks base method.
+func (m *MockWatchServiceServer) List(arg0 context.Context, arg1 *api.ListRequest) (*api.ListResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", arg0, arg1)
+	ret0, _ := ret[0].(*api.ListResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockWatchServiceServerMockRecorder) List(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockWatchServiceServer)(nil).List), arg0, arg1)
+}
+
 // Watch mocks base method.
 func (m *MockWatchServiceServer) Watch(arg0 *api.WatchRequest, arg1 api.WatchService_WatchServer) error {
 	m.ctrl.T.Helper()
@@ -217,6 +622,34 @@ func (mr *MockWatchServiceServerMockRecorder) Watch(arg0, arg1 interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Watch", reflect.TypeOf((*MockWatchServiceServer)(nil).Watch), arg0, arg1)
 }
 
+// WatchMany mocks base method.
+func (m *MockWatchServiceServer) WatchMany(arg0 *api.WatchManyRequest, arg1 api.WatchService_WatchManyServer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WatchMany", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WatchMany indicates an expected call of WatchMany.
+func (mr *MockWatchServiceServerMockRecorder) WatchMany(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WatchMany", reflect.TypeOf((*MockWatchServiceServer)(nil).WatchMany), arg0, arg1)
+}
+
+// WatchStream mocks base method.
+func (m *MockWatchServiceServer) WatchStream(arg0 api.WatchService_WatchStreamServer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WatchStream", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WatchStream indicates an expected call of WatchStream.
+func (mr *MockWatchServiceServerMockRecorder) WatchStream(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WatchStream", reflect.TypeOf((*MockWatchServiceServer)(nil).WatchStream), arg0)
+}
+
 // mustEmbedUnimplementedWatchServiceServer mocks base method.
 func (m *MockWatchServiceServer) mustEmbedUnimplementedWatchServiceServer() {
 	m.ctrl.T.Helper()
@@ -382,3 +815,256 @@ func (mr *MockWatchService_WatchServerMockRecorder) SetTrailer(arg0 interface{})
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTrailer", reflect.TypeOf((*MockWatchService_WatchServer)(nil).SetTrailer), arg0)
 }
+
+// MockWatchService_WatchManyServer is a mock of WatchService_WatchManyServer interface.
+type MockWatchService_WatchManyServer struct {
+	ctrl     *gomock.Controller
+	recorder *MockWatchService_WatchManyServerMockRecorder
+}
+
+// MockWatchService_WatchManyServerMockRecorder is the mock recorder for MockWatchService_WatchManyServer.
+type MockWatchService_WatchManyServerMockRecorder struct {
+	mock *MockWatchService_WatchManyServer
+}
+
+// NewMockWatchService_WatchManyServer creates a new mock instance.
+func NewMockWatchService_WatchManyServer(ctrl *gomock.Controller) *MockWatchService_WatchManyServer {
+	mock := &MockWatchService_WatchManyServer{ctrl: ctrl}
+	mock.recorder = &MockWatchService_WatchManyServerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWatchService_WatchManyServer) EXPECT() *MockWatchService_WatchManyServerMockRecorder {
+	return m.recorder
+}
+
+// Context mocks base method.
+func (m *MockWatchService_WatchManyServer) Context() context.Context {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Context")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// Context indicates an expected call of Context.
+func (mr *MockWatchService_WatchManyServerMockRecorder) Context() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockWatchService_WatchManyServer)(nil).Context))
+}
+
+// RecvMsg mocks base method.
+func (m_2 *MockWatchService_WatchManyServer) RecvMsg(m any) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "RecvMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecvMsg indicates an expected call of RecvMsg.
+func (mr *MockWatchService_WatchManyServerMockRecorder) RecvMsg(m interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecvMsg", reflect.TypeOf((*MockWatchService_WatchManyServer)(nil).RecvMsg), m)
+}
+
+// Send mocks base method.
+func (m *MockWatchService_WatchManyServer) Send(arg0 *api.WatchResponse) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Send", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Send indicates an expected call of Send.
+func (mr *MockWatchService_WatchManyServerMockRecorder) Send(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockWatchService_WatchManyServer)(nil).Send), arg0)
+}
+
+// SendHeader mocks base method.
+func (m *MockWatchService_WatchManyServer) SendHeader(arg0 metadata.MD) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendHeader", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendHeader indicates an expected call of SendHeader.
+func (mr *MockWatchService_WatchManyServerMockRecorder) SendHeader(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendHeader", reflect.TypeOf((*MockWatchService_WatchManyServer)(nil).SendHeader), arg0)
+}
+
+// SendMsg mocks base method.
+func (m_2 *MockWatchService_WatchManyServer) SendMsg(m any) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "SendMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendMsg indicates an expected call of SendMsg.
+func (mr *MockWatchService_WatchManyServerMockRecorder) SendMsg(m interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMsg", reflect.TypeOf((*MockWatchService_WatchManyServer)(nil).SendMsg), m)
+}
+
+// SetHeader mocks base method.
+func (m *MockWatchService_WatchManyServer) SetHeader(arg0 metadata.MD) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetHeader", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetHeader indicates an expected call of SetHeader.
+func (mr *MockWatchService_WatchManyServerMockRecorder) SetHeader(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetHeader", reflect.TypeOf((*MockWatchService_WatchManyServer)(nil).SetHeader), arg0)
+}
+
+// SetTrailer mocks base method.
+func (m *MockWatchService_WatchManyServer) SetTrailer(arg0 metadata.MD) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetTrailer", arg0)
+}
+
+// SetTrailer indicates an expected call of SetTrailer.
+func (mr *MockWatchService_WatchManyServerMockRecorder) SetTrailer(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTrailer", reflect.TypeOf((*MockWatchService_WatchManyServer)(nil).SetTrailer), arg0)
+}
+
+// MockWatchService_WatchStreamServer is a mock of WatchService_WatchStreamServer interface.
+type MockWatchService_WatchStreamServer struct {
+	ctrl     *gomock.Controller
+	recorder *MockWatchService_WatchStreamServerMockRecorder
+}
+
+// MockWatchService_WatchStreamServerMockRecorder is the mock recorder for MockWatchService_WatchStreamServer.
+type MockWatchService_WatchStreamServerMockRecorder struct {
+	mock *MockWatchService_WatchStreamServer
+}
+
+// NewMockWatchService_WatchStreamServer creates a new mock instance.
+func NewMockWatchService_WatchStreamServer(ctrl *gomock.Controller) *MockWatchService_WatchStreamServer {
+	mock := &MockWatchService_WatchStreamServer{ctrl: ctrl}
+	mock.recorder = &MockWatchService_WatchStreamServerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWatchService_WatchStreamServer) EXPECT() *MockWatchService_WatchStreamServerMockRecorder {
+	return m.recorder
+}
+
+// Context mocks base method.
+func (m *MockWatchService_WatchStreamServer) Context() context.Context {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Context")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// Context indicates an expected call of Context.
+func (mr *MockWatchService_WatchStreamServerMockRecorder) Context() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockWatchService_WatchStreamServer)(nil).Context))
+}
+
+// Recv mocks base method.
+func (m *MockWatchService_WatchStreamServer) Recv() (*api.WatchStreamRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Recv")
+	ret0, _ := ret[0].(*api.WatchStreamRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Recv indicates an expected call of Recv.
+func (mr *MockWatchService_WatchStreamServerMockRecorder) Recv() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Recv", reflect.TypeOf((*MockWatchService_WatchStreamServer)(nil).Recv))
+}
+
+// RecvMsg mocks base method.
+func (m_2 *MockWatchService_WatchStreamServer) RecvMsg(m any) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "RecvMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecvMsg indicates an expected call of RecvMsg.
+func (mr *MockWatchService_WatchStreamServerMockRecorder) RecvMsg(m interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecvMsg", reflect.TypeOf((*MockWatchService_WatchStreamServer)(nil).RecvMsg), m)
+}
+
+// Send mocks base method.
+func (m *MockWatchService_WatchStreamServer) Send(arg0 *api.WatchResponse) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Send", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Send indicates an expected call of Send.
+func (mr *MockWatchService_WatchStreamServerMockRecorder) Send(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockWatchService_WatchStreamServer)(nil).Send), arg0)
+}
+
+// SendHeader mocks base method.
+func (m *MockWatchService_WatchStreamServer) SendHeader(arg0 metadata.MD) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendHeader", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendHeader indicates an expected call of SendHeader.
+func (mr *MockWatchService_WatchStreamServerMockRecorder) SendHeader(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendHeader", reflect.TypeOf((*MockWatchService_WatchStreamServer)(nil).SendHeader), arg0)
+}
+
+// SendMsg mocks base method.
+func (m_2 *MockWatchService_WatchStreamServer) SendMsg(m any) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "SendMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendMsg indicates an expected call of SendMsg.
+func (mr *MockWatchService_WatchStreamServerMockRecorder) SendMsg(m interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMsg", reflect.TypeOf((*MockWatchService_WatchStreamServer)(nil).SendMsg), m)
+}
+
+// SetHeader mocks base method.
+func (m *MockWatchService_WatchStreamServer) SetHeader(arg0 metadata.MD) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetHeader", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetHeader indicates an expected call of SetHeader.
+func (mr *MockWatchService_WatchStreamServerMockRecorder) SetHeader(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetHeader", reflect.TypeOf((*MockWatchService_WatchStreamServer)(nil).SetHeader), arg0)
+}
+
+// SetTrailer mocks base method.
+func (m *MockWatchService_WatchStreamServer) SetTrailer(arg0 metadata.MD) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetTrailer", arg0)
+}
+
+// SetTrailer indicates an expected call of SetTrailer.
+func (mr *MockWatchService_WatchStreamServerMockRecorder) SetTrailer(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTrailer", reflect.TypeOf((*MockWatchService_WatchStreamServer)(nil).SetTrailer), arg0)
+}
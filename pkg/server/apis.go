@@ -0,0 +1,171 @@
+package server
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/cmwylie19/watch-informer/api"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/cache"
+)
+
+var (
+	crdsGVR        = schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}
+	apiServicesGVR = schema.GroupVersionResource{Group: "apiregistration.k8s.io", Version: "v1", Resource: "apiservices"}
+)
+
+// waitsForResource reports whether err only means that the resource of req
+// is not served yet and req asked to wait for it.
+func waitsForResource(req *api.WatchRequest, err error) bool {
+	return req.WaitForResource && status.Code(err) == codes.NotFound
+}
+
+func waitingMessage(req *api.WatchRequest) string {
+	if req.Group == "" && req.Version == "" {
+		return fmt.Sprintf("Waiting for %s to be served", req.Resource)
+	}
+	return fmt.Sprintf("Waiting for %s in %s to be served", req.Resource, getFormattedGV(req.Group, req.Version))
+}
+
+// watchAPIs follows the CRDs and APIServices that add and remove resources,
+// so sub can start watching its resource once it is served. Both are listed
+// on return, so whatever is in the lists is seen when resolving. The caller
+// holds attachMu.
+func (s *server) watchAPIs(sub *subscription) error {
+	for _, gvr := range []schema.GroupVersionResource{crdsGVR, apiServicesGVR} {
+		a, err := s.attachInformer(sub.ctx, informerSpec{gvr: gvr}, s.apiHandler(sub), "", 0)
+		if err != nil {
+			return err
+		}
+		sub.apis = append(sub.apis, a)
+	}
+	return nil
+}
+
+// apiHandler resolves the resources of sub again whenever a CRD or
+// APIService changes.
+func (s *server) apiHandler(sub *subscription) cache.ResourceEventHandler {
+	return cache.ResourceEventHandlerDetailedFuncs{
+		AddFunc: func(obj interface{}, isInInitialList bool) {
+			if !isInInitialList {
+				s.refreshResources(sub, objectResourceVersion(obj))
+			}
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			// A CRD only starts being served once it is established
+			if resourceVersionChanged(oldObj, newObj) {
+				s.refreshResources(sub, objectResourceVersion(newObj))
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			// A delete missed while relisting only has the last known
+			// resourceVersion, which tells nothing about when it happened
+			if _, finalStateUnknown := unwrapTombstone(obj); finalStateUnknown {
+				s.refreshResources(sub, 0)
+				return
+			}
+			s.refreshResources(sub, objectResourceVersion(obj))
+		},
+	}
+}
+
+// resourceVersionChanged tells real updates apart from informer resyncs.
+func resourceVersionChanged(oldObj, newObj interface{}) bool {
+	oldMeta, err := meta.Accessor(oldObj)
+	if err != nil {
+		return true
+	}
+	newMeta, err := meta.Accessor(newObj)
+	if err != nil {
+		return true
+	}
+	return oldMeta.GetResourceVersion() != newMeta.GetResourceVersion()
+}
+
+// refreshResources starts watching the resources of sub that became
+// available with the API change at resourceVersion, and sends a
+// RESOURCE_REMOVED event for those that went away.
+func (s *server) refreshResources(sub *subscription, resourceVersion uint64) {
+	sub.attachMu.Lock()
+	defer sub.attachMu.Unlock()
+
+	if sub.isStopped() {
+		return
+	}
+	s.resolver.invalidate(resourceVersion)
+	resources, err := s.resolve(sub.req)
+	if err != nil && status.Code(err) != codes.NotFound {
+		s.Logger.Error(fmt.Sprintf("Failed to resolve the resource of %s: %v", sub.key, err))
+		return
+	}
+
+	removed := subtractResources(sub.resources, resources)
+	added := subtractResources(resources, sub.resources)
+	sub.resources = resources
+	if len(removed) > 0 {
+		s.Logger.Info(fmt.Sprintf("%s of %s no longer served", formatResources(removed), sub.key))
+		for namespace, attachments := range sub.attachments {
+			var kept []*attachment
+			for _, a := range attachments {
+				if slices.Contains(removed, a.gvr) {
+					s.detach(a)
+				} else {
+					kept = append(kept, a)
+				}
+			}
+			sub.attachments[namespace] = kept
+		}
+		sub.push(&queuedEvent{response: newResourceRemovedResponse(removed)})
+		if len(resources) == 0 {
+			sub.push(&queuedEvent{response: newStatusResponse(waitingMessage(sub.req))})
+		}
+	}
+	if len(added) == 0 {
+		return
+	}
+
+	s.Logger.Info(fmt.Sprintf("%s of %s now served", formatResources(added), sub.key))
+	sub.push(&queuedEvent{response: newStatusResponse(fmt.Sprintf("Watching %s", formatResources(added)))})
+	sub.push(&queuedEvent{response: newResolvedResponse(resources)})
+	var initial []*attachment
+	for namespace := range sub.attachments {
+		attachments, err := s.attach(sub, added, namespace, "")
+		if err != nil {
+			s.Logger.Error(fmt.Sprintf("Failed to watch namespace %s: %v", namespace, err))
+			resp := newErrorResponse(apierrors.NewInternalError(err))
+			sub.push(&queuedEvent{response: resp})
+			continue
+		}
+		sub.attachments[namespace] = append(sub.attachments[namespace], attachments...)
+		initial = append(initial, attachments...)
+	}
+	if len(initial) > 0 {
+		go s.sendSynced(sub.ctx, sub, initial)
+	}
+}
+
+// subtractResources returns the resources that are not in others.
+func subtractResources(resources, others []schema.GroupVersionResource) []schema.GroupVersionResource {
+	var missing []schema.GroupVersionResource
+	for _, gvr := range resources {
+		if !slices.Contains(others, gvr) {
+			missing = append(missing, gvr)
+		}
+	}
+	return missing
+}
+
+// formatResources lists resources as group/version/resource.
+func formatResources(resources []schema.GroupVersionResource) string {
+	names := make([]string, len(resources))
+	for i, gvr := range resources {
+		names[i] = getFormattedGV(gvr.Group, gvr.Version) + "/" + gvr.Resource
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,251 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/fake"
+	clienttesting "k8s.io/client-go/testing"
+
+	"github.com/cmwylie19/watch-informer/api"
+)
+
+// widgetsGVR is the resource of the CRD the tests install.
+var widgetsGVR = schema.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "widgets"}
+
+func newTestCRD(name string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apiextensions.k8s.io/v1",
+		"kind":       "CustomResourceDefinition",
+		"metadata": map[string]interface{}{
+			"name":            name,
+			"resourceVersion": "1",
+		},
+	}}
+}
+
+func TestWatchWaitForResource(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := newTestWatchServer(ctrl, newTestPod("default", "nginx"))
+	// The widgets are served as pods once their CRD is installed
+	var served atomic.Bool
+	s.resolver = resolverFunc(func(gvr schema.GroupVersionResource) ([]schema.GroupVersionResource, error) {
+		if !served.Load() {
+			return nil, fmt.Errorf("%w: %s", errResourceNotFound, gvr.Resource)
+		}
+		return []schema.GroupVersionResource{podsGVR}, nil
+	})
+	events, cancel, done := startTestWatch(ctrl, s, &api.WatchRequest{Resource: "widgets", Namespace: "default", WaitForResource: true})
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	if event := waitForEventType(t, events, api.EventType_STATUS); event.Message != "Waiting for widgets to be served" {
+		t.Errorf("Expected a waiting status, got %q", event.Message)
+	}
+	waitForCondition(t, func() bool { return watchStarted(s, crdsGVR) })
+
+	served.Store(true)
+	crds := s.dynamicClient.Resource(crdsGVR)
+	if _, err := crds.Create(context.Background(), newTestCRD("widgets.example.com"), metav1.CreateOptions{}); err != nil {
+		t.Fatalf("Failed to create CRD: %v", err)
+	}
+	if event := waitForEventType(t, events, api.EventType_STATUS); event.Message != "Watching v1/pods" {
+		t.Errorf("Expected a watching status, got %q", event.Message)
+	}
+	waitForEventType(t, events, api.EventType_RESOLVED)
+	waitForEventType(t, events, api.EventType_ADD)
+	waitForEventType(t, events, api.EventType_SYNCED)
+
+	served.Store(false)
+	if err := crds.Delete(context.Background(), "widgets.example.com", metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("Failed to delete CRD: %v", err)
+	}
+	event := waitForEventType(t, events, api.EventType_RESOURCE_REMOVED)
+	if len(event.Resources) != 1 || event.Resources[0].Resource != "pods" {
+		t.Errorf("Expected pods to be removed, got %v", event.Resources)
+	}
+	waitForEventType(t, events, api.EventType_STATUS)
+	// Only the CRD and APIService informers are left
+	waitForCondition(t, func() bool { return s.informers.len() == 2 })
+}
+
+func TestWatchWaitForResourceRefreshesOnce(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := newTestWatchServer(ctrl, newTestPod("default", "nginx"))
+	discovery := newTestDiscovery()
+	s.resolver = newDiscoveryResolver(discovery)
+
+	var streams []<-chan *api.WatchResponse
+	for i := 0; i < 3; i++ {
+		events, cancel, done := startTestWatch(ctrl, s, &api.WatchRequest{Resource: "widgets", Namespace: "default", WaitForResource: true})
+		defer func() {
+			cancel()
+			<-done
+		}()
+		waitForEventType(t, events, api.EventType_STATUS)
+		streams = append(streams, events)
+	}
+	waitForCondition(t, func() bool { return watchStarted(s, crdsGVR) })
+
+	discovery.Lock()
+	discovery.Resources = append(discovery.Resources, &metav1.APIResourceList{
+		GroupVersion: "example.com/v1",
+		APIResources: []metav1.APIResource{
+			{Name: "widgets", SingularName: "widget", Kind: "Widget", Namespaced: true, Verbs: metav1.Verbs{"list", "watch"}},
+		},
+	})
+	discovery.Unlock()
+	calls := len(discovery.Actions())
+
+	crd := newTestCRD("widgets.example.com")
+	crd.SetResourceVersion("2")
+	if _, err := s.dynamicClient.Resource(crdsGVR).Create(context.Background(), crd, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("Failed to create CRD: %v", err)
+	}
+	for _, events := range streams {
+		if event := waitForEventType(t, events, api.EventType_STATUS); event.Message != "Watching example.com/v1/widgets" {
+			t.Errorf("Expected a watching status, got %q", event.Message)
+		}
+	}
+
+	// Resolving after a refresh is served from the cache
+	refreshed := len(discovery.Actions()) - calls
+	s.resolver.invalidate(0)
+	if _, err := s.resolver.resourcesFor(schema.GroupVersionResource{Resource: "widgets"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	perRefresh := len(discovery.Actions()) - calls - refreshed
+	if refreshed != perRefresh {
+		t.Errorf("Expected a single discovery refresh of %d calls for all subscriptions, got %d calls", perRefresh, refreshed)
+	}
+}
+
+func TestWatchWithoutWaitForResource(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := newTestWatchServer(ctrl)
+	s.resolver = resolverFunc(func(gvr schema.GroupVersionResource) ([]schema.GroupVersionResource, error) {
+		return nil, fmt.Errorf("%w: %s", errResourceNotFound, gvr.Resource)
+	})
+	_, cancel, done := startTestWatch(ctrl, s, &api.WatchRequest{Resource: "widgets", Namespace: "default"})
+	defer cancel()
+
+	if err := <-done; err == nil {
+		t.Errorf("Expected the watch to fail")
+	}
+	if s.informers.len() != 0 {
+		t.Errorf("Expected no informers, got %d", s.informers.len())
+	}
+}
+
+func TestWatchWaitForResourceListFails(t *testing.T) {
+	tests := []struct {
+		name    string
+		listErr error
+		code    codes.Code
+	}{
+		{
+			name:    "Forbidden",
+			listErr: apierrors.NewForbidden(crdsGVR.GroupResource(), "", errors.New("no RBAC rule")),
+			code:    codes.PermissionDenied,
+		},
+		{
+			name:    "Unreachable",
+			listErr: errors.New("connection refused"),
+			code:    codes.Unavailable,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			s := newTestWatchServer(ctrl)
+			s.syncTimeout = 200 * time.Millisecond
+			s.dynamicClient.(*fake.FakeDynamicClient).PrependReactor("list", "customresourcedefinitions", func(clienttesting.Action) (bool, runtime.Object, error) {
+				return true, nil, tt.listErr
+			})
+			_, cancel, done := startTestWatch(ctrl, s, &api.WatchRequest{Resource: "widgets", Namespace: "default", WaitForResource: true})
+			defer cancel()
+
+			select {
+			case err := <-done:
+				if status.Code(err) != tt.code {
+					t.Errorf("Expected %s, got %v", tt.code, err)
+				}
+				if !strings.Contains(status.Convert(err).Message(), tt.listErr.Error()) {
+					t.Errorf("Expected the list error in %q", status.Convert(err).Message())
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("Timed out waiting for the watch to fail")
+			}
+			waitForCondition(t, func() bool { return s.informers.len() == 0 })
+		})
+	}
+}
+
+func TestWatchStreamWaitForResourceForbidden(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := newTestWatchServer(ctrl, newTestPod("default", "nginx"))
+	s.dynamicClient.(*fake.FakeDynamicClient).PrependReactor("list", "customresourcedefinitions", func(clienttesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewForbidden(crdsGVR.GroupResource(), "", errors.New("no RBAC rule"))
+	})
+	requests, events, cancel, done := startTestWatchStream(ctrl, s)
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	requests <- subscribeRequest(&api.WatchRequest{Resource: "widgets", Namespace: "default", WaitForResource: true, Id: "widgets"})
+	event := waitForEventType(t, events, api.EventType_ERROR)
+	if event.SubscriptionId != "widgets" {
+		t.Errorf("Expected subscription widgets, got %q", event.SubscriptionId)
+	}
+	var st metav1.Status
+	if err := json.Unmarshal(event.Object, &st); err != nil {
+		t.Fatalf("Expected a Status object: %v", err)
+	}
+	if st.Code != http.StatusForbidden {
+		t.Errorf("Expected 403 Forbidden, got %d", st.Code)
+	}
+
+	// The stream goes on with its other subscriptions
+	requests <- subscribeRequest(&api.WatchRequest{Version: "v1", Resource: "pod", Namespace: "default", Id: "pods"})
+	waitForEventType(t, events, api.EventType_RESOLVED)
+	waitForEventType(t, events, api.EventType_ADD)
+}
+
+// watchStarted reports whether an informer watches gvr, so that changes to
+// it are no longer missed by the fake client.
+func watchStarted(s *server, gvr schema.GroupVersionResource) bool {
+	for _, action := range s.dynamicClient.(*fake.FakeDynamicClient).Actions() {
+		if action.GetVerb() == "watch" && action.GetResource() == gvr {
+			return true
+		}
+	}
+	return false
+}
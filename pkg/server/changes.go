@@ -0,0 +1,73 @@
+package server
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/cmwylie19/watch-informer/api"
+)
+
+// ignoredChanges are the paths of an object whose changes do not make an
+// UPDATE event worth sending. A nil ignoredChanges ignores nothing.
+type ignoredChanges [][]string
+
+// newIgnoredChanges returns the paths req ignores. The resourceVersion and
+// managedFields change on every write, so they are ignored along with them.
+func newIgnoredChanges(req *api.WatchRequest) ignoredChanges {
+	if !req.IgnoreStatusChanges && len(req.IgnorePaths) == 0 {
+		return nil
+	}
+	ignored := ignoredChanges{{"metadata", "resourceVersion"}, {"metadata", "managedFields"}}
+	if req.IgnoreStatusChanges {
+		ignored = append(ignored, []string{"status"})
+	}
+	for _, path := range req.IgnorePaths {
+		ignored = append(ignored, strings.Split(path, "."))
+	}
+	return ignored
+}
+
+// changed reports whether newObj differs from oldObj outside of the ignored
+// paths.
+func (ignored ignoredChanges) changed(oldObj, newObj interface{}) bool {
+	if ignored == nil {
+		return true
+	}
+	oldContent, ok := objectContent(oldObj)
+	if !ok {
+		return true
+	}
+	newContent, ok := objectContent(newObj)
+	if !ok {
+		return true
+	}
+	return !reflect.DeepEqual(removePaths(oldContent, ignored), removePaths(newContent, ignored))
+}
+
+// removePaths returns content without the values at paths. Like the
+// projection, it copies every map it changes.
+func removePaths(content map[string]interface{}, paths [][]string) map[string]interface{} {
+	for _, path := range paths {
+		content = removePath(content, path)
+	}
+	return content
+}
+
+func removePath(content map[string]interface{}, path []string) map[string]interface{} {
+	value, ok := content[path[0]]
+	if !ok {
+		return content
+	}
+	if len(path) == 1 {
+		content = copyMap(content)
+		delete(content, path[0])
+		return content
+	}
+	child, ok := value.(map[string]interface{})
+	if !ok {
+		return content
+	}
+	content = copyMap(content)
+	content[path[0]] = removePath(child, path[1:])
+	return content
+}
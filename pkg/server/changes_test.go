@@ -0,0 +1,94 @@
+package server
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/cmwylie19/watch-informer/api"
+)
+
+func TestIgnoredChanges(t *testing.T) {
+	running := newTestPodInPhase("nginx", "Running")
+	running.Object["spec"] = map[string]interface{}{"nodeName": "a"}
+
+	statusChanged := running.DeepCopy()
+	statusChanged.SetResourceVersion("2")
+	statusChanged.Object["status"] = map[string]interface{}{"phase": "Failed"}
+
+	annotated := running.DeepCopy()
+	annotated.SetResourceVersion("2")
+	annotated.SetAnnotations(map[string]string{"team": "payments"})
+	annotated.Object["metadata"].(map[string]interface{})["managedFields"] = []interface{}{
+		map[string]interface{}{"manager": "kubectl"},
+	}
+
+	specChanged := statusChanged.DeepCopy()
+	specChanged.Object["spec"] = map[string]interface{}{"nodeName": "b"}
+
+	tests := []struct {
+		name     string
+		req      *api.WatchRequest
+		newObj   *unstructured.Unstructured
+		expected bool
+	}{
+		{name: "Nothing ignored", req: &api.WatchRequest{}, newObj: statusChanged, expected: true},
+		{name: "Status change", req: &api.WatchRequest{IgnoreStatusChanges: true}, newObj: statusChanged, expected: false},
+		{name: "Spec change", req: &api.WatchRequest{IgnoreStatusChanges: true}, newObj: specChanged, expected: true},
+		{name: "Ignored path", req: &api.WatchRequest{IgnorePaths: []string{"metadata.annotations"}}, newObj: annotated, expected: false},
+		{name: "Other path", req: &api.WatchRequest{IgnorePaths: []string{"metadata.labels"}}, newObj: annotated, expected: true},
+		{name: "Missing path", req: &api.WatchRequest{IgnorePaths: []string{"spec.nodeName.value", "data"}}, newObj: specChanged, expected: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			original := running.DeepCopy()
+			if changed := newIgnoredChanges(tc.req).changed(running, tc.newObj); changed != tc.expected {
+				t.Errorf("expected: %t, got: %t", tc.expected, changed)
+			}
+			if !reflect.DeepEqual(running, original) {
+				t.Errorf("Expected the object to be left unchanged, got %v", running.Object)
+			}
+		})
+	}
+}
+
+func TestWatchIgnoreStatusChanges(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := newTestWatchServer(ctrl, newTestPodInPhase("nginx", "Pending"))
+	events, cancel, done := startTestWatch(ctrl, s, &api.WatchRequest{
+		Version:             "v1",
+		Resource:            "pod",
+		Namespace:           "default",
+		IgnoreStatusChanges: true,
+	})
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	waitForEventType(t, events, api.EventType_RESOLVED)
+	waitForEventType(t, events, api.EventType_ADD)
+	waitForEventType(t, events, api.EventType_SYNCED)
+
+	pods := s.dynamicClient.Resource(podsGVR).Namespace("default")
+	running := newTestPodInPhase("nginx", "Running")
+	running.SetResourceVersion("2")
+	if _, err := pods.Update(context.Background(), running, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("Failed to update pod: %v", err)
+	}
+	labeled := running.DeepCopy()
+	labeled.SetResourceVersion("3")
+	labeled.SetLabels(map[string]string{"app": "nginx"})
+	if _, err := pods.Update(context.Background(), labeled, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("Failed to update pod: %v", err)
+	}
+	if event := waitForEventType(t, events, api.EventType_UPDATE); event.ResourceVersion != "3" {
+		t.Errorf("Expected only the label change, got resourceVersion %s", event.ResourceVersion)
+	}
+}
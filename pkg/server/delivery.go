@@ -0,0 +1,208 @@
+package server
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/cmwylie19/watch-informer/api"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// maxUnackedEvents bounds the events retained for redelivery. Sending
+	// waits for acks once that many are outstanding.
+	maxUnackedEvents = 1000
+	// deliveryRetention is how long the unacked events of a disconnected
+	// client are kept for it to reconnect.
+	deliveryRetention = 5 * time.Minute
+)
+
+var errDeliveryTakenOver = status.Error(codes.Aborted, "delivery was taken over by a new stream")
+
+// delivery numbers the events sent to a client and holds them back as its
+// flow control asks. A delivery with an id also retains sent events until
+// they are acked, so a client reconnecting with the same id gets them again.
+type delivery struct {
+	id          string
+	mu          sync.Mutex
+	paused      bool
+	maxInFlight uint64
+	sequence    uint64
+	acked       uint64
+	unacked     []*api.WatchResponse
+	// changed is closed and replaced whenever sending may become possible.
+	changed chan struct{}
+
+	// Owned by the deliveryRegistry
+	generation uint64
+	cancel     context.CancelCauseFunc
+	expiry     *time.Timer
+}
+
+func newDelivery(id string) *delivery {
+	return &delivery{id: id, changed: make(chan struct{})}
+}
+
+// wait blocks until another event may be sent.
+func (d *delivery) wait(ctx context.Context) error {
+	for {
+		d.mu.Lock()
+		ready := !d.paused &&
+			(d.maxInFlight == 0 || d.sequence-d.acked < d.maxInFlight) &&
+			len(d.unacked) < maxUnackedEvents
+		changed := d.changed
+		d.mu.Unlock()
+		if ready {
+			return nil
+		}
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// sent numbers resp, and retains it until it is acked when the delivery has
+// an id.
+func (d *delivery) sent(resp *api.WatchResponse) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.sequence++
+	resp.Sequence = d.sequence
+	if d.id != "" {
+		d.unacked = append(d.unacked, resp)
+	}
+}
+
+// ack records that the client has processed every event up to sequence.
+// Sequences that were never sent are ignored.
+func (d *delivery) ack(sequence uint64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if sequence <= d.acked || sequence > d.sequence {
+		return
+	}
+	d.acked = sequence
+	i := 0
+	for i < len(d.unacked) && d.unacked[i].Sequence <= sequence {
+		d.unacked[i] = nil
+		i++
+	}
+	d.unacked = d.unacked[i:]
+	d.notify()
+}
+
+// update applies the flow control asked for by the client.
+func (d *delivery) update(msg *api.FlowControl) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.paused = msg.Pause
+	d.maxInFlight = uint64(msg.MaxInFlight)
+	d.notify()
+}
+
+// redeliveries returns copies of the unacked events to send again.
+func (d *delivery) redeliveries() []*api.WatchResponse {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	resps := make([]*api.WatchResponse, len(d.unacked))
+	for i, resp := range d.unacked {
+		resps[i] = proto.Clone(resp).(*api.WatchResponse)
+		resps[i].Redelivered = true
+	}
+	return resps
+}
+
+func (d *delivery) notify() {
+	close(d.changed)
+	d.changed = make(chan struct{})
+}
+
+// deliveryRegistry keeps the deliveries with an id while a stream is
+// attached to them and for deliveryRetention afterwards.
+type deliveryRegistry struct {
+	mu         sync.Mutex
+	deliveries map[string]*delivery
+	retention  time.Duration
+}
+
+func newDeliveryRegistry(retention time.Duration) *deliveryRegistry {
+	return &deliveryRegistry{
+		deliveries: make(map[string]*delivery),
+		retention:  retention,
+	}
+}
+
+// attach hands the delivery for id to a new stream, ending any stream that
+// is still attached to it. The returned func detaches the stream again,
+// retaining the events it never sent along with the unacked ones.
+func (r *deliveryRegistry) attach(id string, cancel context.CancelCauseFunc) (*delivery, func(unsent []*api.WatchResponse)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	d, ok := r.deliveries[id]
+	if !ok {
+		d = newDelivery(id)
+		r.deliveries[id] = d
+	}
+	if d.cancel != nil {
+		d.cancel(errDeliveryTakenOver)
+	}
+	if d.expiry != nil {
+		d.expiry.Stop()
+		d.expiry = nil
+	}
+	d.generation++
+	d.cancel = cancel
+
+	// Flow control belongs to the stream, so a new one starts afresh
+	d.update(&api.FlowControl{})
+
+	generation := d.generation
+	return d, func(unsent []*api.WatchResponse) { r.detach(d, generation, unsent) }
+}
+
+// detach numbers the unsent events of the stream and retains them after
+// its unacked ones, then starts the retention period of d. Neither happens
+// if another stream has attached to d since, as the events would reach it
+// out of order.
+func (r *deliveryRegistry) detach(d *delivery, generation uint64, unsent []*api.WatchResponse) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if d.generation != generation {
+		return
+	}
+	for _, resp := range unsent {
+		d.sent(resp)
+	}
+	d.cancel = nil
+	d.expiry = time.AfterFunc(r.retention, func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		if d.generation == generation && r.deliveries[d.id] == d {
+			delete(r.deliveries, d.id)
+		}
+	})
+}
+
+// get returns the delivery for id, if it is still retained.
+func (r *deliveryRegistry) get(id string) (*delivery, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	d, ok := r.deliveries[id]
+	return d, ok
+}
+
+// len returns the number of retained deliveries.
+func (r *deliveryRegistry) len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.deliveries)
+}
@@ -0,0 +1,165 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/cmwylie19/watch-informer/api"
+)
+
+func TestDeliveryAck(t *testing.T) {
+	d := newDelivery("client")
+	for i := 0; i < 3; i++ {
+		d.sent(&api.WatchResponse{})
+	}
+	d.ack(2)
+	d.ack(1)  // already acked
+	d.ack(10) // never sent
+
+	redeliveries := d.redeliveries()
+	if len(redeliveries) != 1 || redeliveries[0].Sequence != 3 || !redeliveries[0].Redelivered {
+		t.Errorf("Expected event 3 to be redelivered, got %v", redeliveries)
+	}
+	if d.unacked[0].Redelivered {
+		t.Errorf("Expected the retained event to be left untouched")
+	}
+
+	anonymous := newDelivery("")
+	anonymous.sent(&api.WatchResponse{})
+	if len(anonymous.redeliveries()) != 0 {
+		t.Errorf("Expected events not to be retained without an id")
+	}
+}
+
+func TestDeliveryWaitForAcks(t *testing.T) {
+	d := newDelivery("client")
+	for i := 0; i < maxUnackedEvents; i++ {
+		d.sent(&api.WatchResponse{})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := d.wait(ctx); err == nil {
+		t.Fatalf("Expected wait to block while the retention is full")
+	}
+
+	d.ack(1)
+	if err := d.wait(context.Background()); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestDeliveryRegistry(t *testing.T) {
+	registry := newDeliveryRegistry(50 * time.Millisecond)
+
+	firstCtx, firstCancel := context.WithCancelCause(context.Background())
+	first, detachFirst := registry.attach("client", firstCancel)
+	first.sent(&api.WatchResponse{})
+
+	_, secondCancel := context.WithCancelCause(context.Background())
+	second, detachSecond := registry.attach("client", secondCancel)
+	if first != second {
+		t.Fatalf("Expected the same delivery for the same id")
+	}
+	if !errors.Is(context.Cause(firstCtx), errDeliveryTakenOver) {
+		t.Errorf("Expected the first stream to be taken over, got %v", context.Cause(firstCtx))
+	}
+
+	// The stream that was taken over must not start the retention period
+	detachFirst(nil)
+	time.Sleep(100 * time.Millisecond)
+	if _, ok := registry.get("client"); !ok {
+		t.Fatalf("Expected the delivery to be kept while attached")
+	}
+
+	detachSecond(nil)
+	waitForCondition(t, func() bool { return registry.len() == 0 })
+}
+
+func TestWatchRedeliversUnacked(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := newTestWatchServer(ctrl, newTestPod("default", "nginx"))
+	req := &api.WatchRequest{Version: "v1", Resource: "pod", Namespace: "default", DeliveryId: "client"}
+
+	events, cancel, done := startTestWatch(ctrl, s, req)
+	waitForEventType(t, events, api.EventType_RESOLVED)
+	if event := waitForEventType(t, events, api.EventType_ADD); event.Sequence != 2 {
+		t.Errorf("Expected sequence 2, got %d", event.Sequence)
+	}
+	waitForEventType(t, events, api.EventType_SYNCED)
+	if _, err := s.Ack(context.Background(), &api.AckRequest{DeliveryId: "client", Sequence: 2}); err != nil {
+		t.Fatalf("Failed to ack: %v", err)
+	}
+	cancel()
+	<-done
+
+	events, cancel, done = startTestWatch(ctrl, s, req)
+	defer func() {
+		cancel()
+		<-done
+	}()
+	if event := waitForEventType(t, events, api.EventType_SYNCED); event.Sequence != 3 || !event.Redelivered {
+		t.Errorf("Expected SYNCED 3 to be redelivered, got %d redelivered=%t", event.Sequence, event.Redelivered)
+	}
+	waitForEventType(t, events, api.EventType_RESOLVED)
+	if event := waitForEventType(t, events, api.EventType_ADD); event.Sequence != 5 || event.Redelivered {
+		t.Errorf("Expected a new ADD 5, got %d redelivered=%t", event.Sequence, event.Redelivered)
+	}
+}
+
+func TestWatchStreamRedeliversQueued(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := newTestWatchServer(ctrl, newTestPod("default", "nginx"))
+	options := &api.WatchStreamRequest{Request: &api.WatchStreamRequest_Options{Options: &api.StreamOptions{DeliveryId: "client"}}}
+
+	// Only RESOLVED is sent, the ADD and SYNCED stay queued behind it
+	requests, events, cancel, done := startTestWatchStream(ctrl, s)
+	requests <- options
+	requests <- &api.WatchStreamRequest{Request: &api.WatchStreamRequest_FlowControl{FlowControl: &api.FlowControl{MaxInFlight: 1}}}
+	requests <- subscribeRequest(&api.WatchRequest{Version: "v1", Resource: "pod", Namespace: "default", Id: "pods"})
+	waitForEventType(t, events, api.EventType_RESOLVED)
+	waitForCondition(t, func() bool {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		for _, sess := range s.sessions {
+			return sess.events.len() == 2
+		}
+		return false
+	})
+	cancel()
+	<-done
+
+	requests, events, cancel, done = startTestWatchStream(ctrl, s)
+	defer func() {
+		cancel()
+		<-done
+	}()
+	requests <- options
+	for i, eventType := range []api.EventType{api.EventType_RESOLVED, api.EventType_ADD, api.EventType_SYNCED} {
+		event := waitForEventType(t, events, eventType)
+		if event.Sequence != uint64(i+1) || !event.Redelivered || event.SubscriptionId != "pods" {
+			t.Errorf("Expected %s %d of pods to be redelivered, got %d of %q redelivered=%t", eventType, i+1, event.Sequence, event.SubscriptionId, event.Redelivered)
+		}
+	}
+}
+
+func TestAckUnknownDelivery(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := newTestWatchServer(ctrl)
+	_, err := s.Ack(context.Background(), &api.AckRequest{DeliveryId: "client", Sequence: 1})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("Expected NotFound, got %v", err)
+	}
+}
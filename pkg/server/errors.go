@@ -0,0 +1,121 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var (
+	errResourceNotFound     = errors.New("resource not found")
+	errClientNotInitialized = status.Error(codes.Unavailable, "dynamic client is not initialized")
+)
+
+// invalidArgument returns an InvalidArgument status naming the request field
+// that was wrong.
+func invalidArgument(field, description string) error {
+	st := status.New(codes.InvalidArgument, fmt.Sprintf("invalid %s: %s", field, description))
+	return withDetails(st, &errdetails.BadRequest{
+		FieldViolations: []*errdetails.BadRequest_FieldViolation{{Field: field, Description: description}},
+	})
+}
+
+// withFieldPrefix qualifies the fields named by an InvalidArgument status
+// with prefix, for requests nested in another message.
+func withFieldPrefix(err error, prefix string) error {
+	st, ok := status.FromError(err)
+	if !ok || st.Code() != codes.InvalidArgument {
+		return err
+	}
+	for _, detail := range st.Details() {
+		if badRequest, ok := detail.(*errdetails.BadRequest); ok && len(badRequest.FieldViolations) > 0 {
+			violation := badRequest.FieldViolations[0]
+			return invalidArgument(prefix+violation.Field, violation.Description)
+		}
+	}
+	return err
+}
+
+// resolveError converts a failure to resolve gvr against the API server into
+// a status telling the client whether to fix the request or retry.
+func resolveError(gvr schema.GroupVersionResource, err error) error {
+	var code codes.Code
+	switch {
+	case errors.Is(err, errResourceNotFound), apierrors.IsNotFound(err):
+		code = codes.NotFound
+	case apierrors.IsForbidden(err), apierrors.IsUnauthorized(err):
+		code = codes.PermissionDenied
+	case meta.IsAmbiguousError(err):
+		code = codes.InvalidArgument
+	default:
+		code = codes.Unavailable
+	}
+	return withDetails(status.New(code, err.Error()), &errdetails.ResourceInfo{
+		ResourceType: "GroupVersionResource",
+		ResourceName: gvr.String(),
+		Description:  err.Error(),
+	})
+}
+
+// apiError converts an error from the API server into a status.
+func apiError(err error) error {
+	var code codes.Code
+	switch {
+	case apierrors.IsResourceExpired(err), apierrors.IsGone(err):
+		code = codes.Aborted
+	case apierrors.IsNotFound(err):
+		code = codes.NotFound
+	case apierrors.IsForbidden(err), apierrors.IsUnauthorized(err):
+		code = codes.PermissionDenied
+	case apierrors.IsBadRequest(err), apierrors.IsInvalid(err):
+		code = codes.InvalidArgument
+	default:
+		code = codes.Unavailable
+	}
+	return status.Error(code, err.Error())
+}
+
+// withDetails attaches details to st, falling back to st alone should they
+// fail to marshal.
+func withDetails(st *status.Status, details ...protoadapt.MessageV1) error {
+	detailed, err := st.WithDetails(details...)
+	if err != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
+
+// apiStatusFromError converts a status into the Kubernetes Status reported
+// by ERROR events.
+func apiStatusFromError(err error) apierrors.APIStatus {
+	st := status.Convert(err)
+	switch st.Code() {
+	case codes.InvalidArgument:
+		return apierrors.NewBadRequest(st.Message())
+	case codes.NotFound:
+		return newStatusError(http.StatusNotFound, metav1.StatusReasonNotFound, st.Message())
+	case codes.PermissionDenied:
+		return newStatusError(http.StatusForbidden, metav1.StatusReasonForbidden, st.Message())
+	case codes.Unavailable:
+		return apierrors.NewServiceUnavailable(st.Message())
+	}
+	return apierrors.NewInternalError(err)
+}
+
+func newStatusError(code int32, reason metav1.StatusReason, message string) *apierrors.StatusError {
+	return &apierrors.StatusError{ErrStatus: metav1.Status{
+		Status:  metav1.StatusFailure,
+		Code:    code,
+		Reason:  reason,
+		Message: message,
+	}}
+}
@@ -0,0 +1,143 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/cmwylie19/watch-informer/api"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/cache"
+)
+
+// newWatchResponse builds the response for an informer event. Besides the
+// legacy details string it carries the raw object and its identifying
+// metadata so clients can route events without decoding the payload.
+// Tombstones are unwrapped to the last known object.
+func newWatchResponse(eventType api.EventType, obj interface{}) *api.WatchResponse {
+	return newProjectedWatchResponse(eventType, obj, nil, schema.GroupVersionKind{})
+}
+
+// newProjectedWatchResponse is newWatchResponse with the payload trimmed by
+// p. The identifying metadata still comes from the whole object, except for
+// its apiVersion and kind when kind is set: the objects of metadata-only
+// watches are all PartialObjectMetadata.
+func newProjectedWatchResponse(eventType api.EventType, obj interface{}, p *projection, kind schema.GroupVersionKind) *api.WatchResponse {
+	obj, finalStateUnknown := unwrapTombstone(obj)
+	resp := &api.WatchResponse{
+		EventType:         eventType.String(),
+		Type:              eventType,
+		FinalStateUnknown: finalStateUnknown,
+	}
+
+	data, err := json.Marshal(p.apply(obj))
+	if err != nil {
+		resp.Details = fmt.Sprintf("Error converting to JSON: %v", err)
+	} else {
+		resp.Details = string(data)
+		resp.Object = data
+	}
+
+	if !kind.Empty() {
+		resp.ApiVersion, resp.Kind = kind.ToAPIVersionAndKind()
+	} else if typeAccessor, err := meta.TypeAccessor(obj); err == nil {
+		resp.ApiVersion = typeAccessor.GetAPIVersion()
+		resp.Kind = typeAccessor.GetKind()
+	}
+	if accessor, err := meta.Accessor(obj); err == nil {
+		resp.Namespace = accessor.GetNamespace()
+		resp.Name = accessor.GetName()
+		resp.Uid = string(accessor.GetUID())
+		resp.ResourceVersion = accessor.GetResourceVersion()
+	}
+	return resp
+}
+
+// unwrapTombstone returns the last known object of a DELETE the informer
+// missed, and whether obj was such a tombstone.
+func unwrapTombstone(obj interface{}) (interface{}, bool) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		return tombstone.Obj, true
+	}
+	return obj, false
+}
+
+// objectContent returns the fields of an informer object. Typed objects, like
+// the PartialObjectMetadata of metadata-only watches, are converted.
+func objectContent(obj interface{}) (map[string]interface{}, bool) {
+	switch o := obj.(type) {
+	case interface{ UnstructuredContent() map[string]interface{} }:
+		return o.UnstructuredContent(), true
+	case runtime.Object:
+		content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(o)
+		return content, err == nil
+	}
+	return nil, false
+}
+
+// newErrorResponse wraps a Kubernetes API error in an ERROR event whose
+// object is the error's Status, mirroring Kubernetes watch semantics.
+func newErrorResponse(err apierrors.APIStatus) *api.WatchResponse {
+	status := err.Status()
+	status.APIVersion = "v1"
+	status.Kind = "Status"
+	return newWatchResponse(api.EventType_ERROR, &status)
+}
+
+// newSyncedResponse builds the SYNCED event sent once the initial list has
+// been delivered. Its resourceVersion can be used to resume the watch.
+func newSyncedResponse(resourceVersion string) *api.WatchResponse {
+	return &api.WatchResponse{
+		EventType:       api.EventType_SYNCED.String(),
+		Type:            api.EventType_SYNCED,
+		ResourceVersion: resourceVersion,
+	}
+}
+
+// newUnsubscribedResponse builds the UNSUBSCRIBED event that follows the last
+// event of a subscription.
+func newUnsubscribedResponse() *api.WatchResponse {
+	return &api.WatchResponse{
+		EventType: api.EventType_UNSUBSCRIBED.String(),
+		Type:      api.EventType_UNSUBSCRIBED,
+	}
+}
+
+// newResolvedResponse builds the RESOLVED event that tells the client which
+// resources its request resolved to.
+func newResolvedResponse(resources []schema.GroupVersionResource) *api.WatchResponse {
+	resp := &api.WatchResponse{
+		EventType: api.EventType_RESOLVED.String(),
+		Type:      api.EventType_RESOLVED,
+	}
+	for _, gvr := range resources {
+		resp.Resources = append(resp.Resources, &api.GroupVersionResource{
+			Group:    gvr.Group,
+			Version:  gvr.Version,
+			Resource: gvr.Resource,
+		})
+	}
+	return resp
+}
+
+// newStatusResponse builds a STATUS event telling the client what its
+// subscription is doing.
+func newStatusResponse(message string) *api.WatchResponse {
+	return &api.WatchResponse{
+		EventType: api.EventType_STATUS.String(),
+		Type:      api.EventType_STATUS,
+		Message:   message,
+	}
+}
+
+// newResourceRemovedResponse builds the RESOURCE_REMOVED event for resources
+// the API server stopped serving.
+func newResourceRemovedResponse(resources []schema.GroupVersionResource) *api.WatchResponse {
+	resp := newResolvedResponse(resources)
+	resp.EventType = api.EventType_RESOURCE_REMOVED.String()
+	resp.Type = api.EventType_RESOURCE_REMOVED
+	return resp
+}
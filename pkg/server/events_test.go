@@ -0,0 +1,89 @@
+package server
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/cmwylie19/watch-informer/api"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/cache"
+)
+
+func TestNewWatchResponse(t *testing.T) {
+	pod := newTestPod("default", "nginx")
+	pod.SetUID("1234")
+
+	resp := newWatchResponse(api.EventType_UPDATE, pod)
+
+	if resp.EventType != "UPDATE" || resp.Type != api.EventType_UPDATE {
+		t.Errorf("Expected UPDATE event, got %s/%s", resp.EventType, resp.Type)
+	}
+	if resp.ApiVersion != "v1" || resp.Kind != "Pod" {
+		t.Errorf("Expected v1/Pod, got %s/%s", resp.ApiVersion, resp.Kind)
+	}
+	if resp.Namespace != "default" || resp.Name != "nginx" {
+		t.Errorf("Expected default/nginx, got %s/%s", resp.Namespace, resp.Name)
+	}
+	if resp.Uid != "1234" || resp.ResourceVersion != "1" {
+		t.Errorf("Expected uid 1234 and resourceVersion 1, got %s and %s", resp.Uid, resp.ResourceVersion)
+	}
+	if resp.Details != string(resp.Object) {
+		t.Errorf("Expected details to mirror the object, got %s", resp.Details)
+	}
+
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(resp.Object, &decoded); err != nil {
+		t.Fatalf("Expected object to be valid JSON: %v", err)
+	}
+	if decoded["kind"] != "Pod" {
+		t.Errorf("Expected decoded kind Pod, got %v", decoded["kind"])
+	}
+}
+
+func TestNewProjectedWatchResponseKind(t *testing.T) {
+	pod := &metav1.PartialObjectMetadata{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "meta.k8s.io/v1", Kind: "PartialObjectMetadata"},
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "nginx"},
+	}
+
+	resp := newProjectedWatchResponse(api.EventType_ADD, pod, nil, schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"})
+	if resp.ApiVersion != "apps/v1" || resp.Kind != "Deployment" {
+		t.Errorf("Expected apps/v1/Deployment, got %s/%s", resp.ApiVersion, resp.Kind)
+	}
+	if resp.Name != "nginx" {
+		t.Errorf("Expected nginx, got %s", resp.Name)
+	}
+}
+
+func TestNewWatchResponseNonObject(t *testing.T) {
+	resp := newWatchResponse(api.EventType_ADD, "not an object")
+
+	if resp.Details != `"not an object"` {
+		t.Errorf("Expected details to hold the JSON value, got %s", resp.Details)
+	}
+	if resp.Kind != "" || resp.Name != "" {
+		t.Errorf("Expected no metadata for a non-object, got %s/%s", resp.Kind, resp.Name)
+	}
+}
+
+func TestNewWatchResponseTombstone(t *testing.T) {
+	pod := newTestPod("default", "nginx")
+	resp := newWatchResponse(api.EventType_DELETE, cache.DeletedFinalStateUnknown{Key: "default/nginx", Obj: pod})
+
+	if !resp.FinalStateUnknown {
+		t.Errorf("Expected finalStateUnknown to be set")
+	}
+	if resp.Kind != "Pod" || resp.Name != "nginx" || resp.ResourceVersion != "1" {
+		t.Errorf("Expected the last known pod, got %s %s at %s", resp.Kind, resp.Name, resp.ResourceVersion)
+	}
+	expected, _ := json.Marshal(pod)
+	if string(resp.Object) != string(expected) {
+		t.Errorf("expected: %s, got: %s", expected, resp.Object)
+	}
+
+	if resp := newWatchResponse(api.EventType_DELETE, pod); resp.FinalStateUnknown {
+		t.Errorf("Expected finalStateUnknown to be unset for a regular DELETE")
+	}
+}
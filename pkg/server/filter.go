@@ -0,0 +1,85 @@
+package server
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/cmwylie19/watch-informer/api"
+
+	"github.com/google/cel-go/cel"
+)
+
+// filterCostLimit bounds the work a filter may do per event.
+const filterCostLimit = 1000000
+
+var (
+	filterEnvOnce sync.Once
+	filterEnv     *cel.Env
+	filterEnvErr  error
+)
+
+// eventFilter is the compiled filter expression of a subscription. It sees
+// the object, the previous object on UPDATE events (null otherwise) and the
+// event type.
+type eventFilter struct {
+	program cel.Program
+}
+
+func newFilterEnv() (*cel.Env, error) {
+	filterEnvOnce.Do(func() {
+		filterEnv, filterEnvErr = cel.NewEnv(
+			cel.Variable("object", cel.DynType),
+			cel.Variable("oldObject", cel.DynType),
+			cel.Variable("eventType", cel.StringType),
+		)
+	})
+	return filterEnv, filterEnvErr
+}
+
+// compileFilter compiles a filter expression, returning an InvalidArgument
+// status when it does not compile to a bool.
+func compileFilter(expression string) (*eventFilter, error) {
+	env, err := newFilterEnv()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create filter environment: %w", err)
+	}
+	ast, issues := env.Compile(expression)
+	if issues.Err() != nil {
+		return nil, invalidArgument("filter", issues.Err().Error())
+	}
+	if ast.OutputType() != cel.BoolType && ast.OutputType() != cel.DynType {
+		return nil, invalidArgument("filter", fmt.Sprintf("must evaluate to a bool, not %s", ast.OutputType()))
+	}
+	program, err := env.Program(ast, cel.CostLimit(filterCostLimit))
+	if err != nil {
+		return nil, invalidArgument("filter", err.Error())
+	}
+	return &eventFilter{program: program}, nil
+}
+
+// matches reports whether an event passes the filter. Events the filter
+// fails on, e.g. by reading a field the object lacks, do not.
+func (f *eventFilter) matches(eventType api.EventType, obj, oldObj interface{}) (bool, error) {
+	out, _, err := f.program.Eval(map[string]interface{}{
+		"object":    filterInput(obj),
+		"oldObject": filterInput(oldObj),
+		"eventType": eventType.String(),
+	})
+	if err != nil {
+		return false, err
+	}
+	matched, ok := out.Value().(bool)
+	if !ok {
+		return false, fmt.Errorf("filter evaluated to %v, not a bool", out.Value())
+	}
+	return matched, nil
+}
+
+// filterInput returns the content of an informer object as seen by filters.
+func filterInput(obj interface{}) interface{} {
+	obj, _ = unwrapTombstone(obj)
+	if content, ok := objectContent(obj); ok {
+		return content
+	}
+	return obj
+}
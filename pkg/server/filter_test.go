@@ -0,0 +1,96 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/cmwylie19/watch-informer/api"
+)
+
+func newTestPodInPhase(name, phase string) *unstructured.Unstructured {
+	pod := newTestPod("default", name)
+	pod.Object["status"] = map[string]interface{}{"phase": phase}
+	return pod
+}
+
+func TestEventFilter(t *testing.T) {
+	running, failed := newTestPodInPhase("nginx", "Running"), newTestPodInPhase("nginx", "Failed")
+	tests := []struct {
+		name       string
+		expression string
+		eventType  api.EventType
+		obj        interface{}
+		oldObj     interface{}
+		expected   bool
+	}{
+		{name: "Field", expression: `object.status.phase == "Running"`, eventType: api.EventType_ADD, obj: running, expected: true},
+		{name: "Field mismatch", expression: `object.status.phase == "Running"`, eventType: api.EventType_ADD, obj: failed, expected: false},
+		{
+			name:       "Changed to",
+			expression: `eventType == "UPDATE" && object.status.phase == "Failed" && oldObject.status.phase != "Failed"`,
+			eventType:  api.EventType_UPDATE, obj: failed, oldObj: running, expected: true,
+		},
+		{
+			name:       "Unchanged",
+			expression: `eventType == "UPDATE" && object.status.phase == "Failed" && oldObject.status.phase != "Failed"`,
+			eventType:  api.EventType_UPDATE, obj: failed, oldObj: failed, expected: false,
+		},
+		{name: "Missing field", expression: `object.type == "kubernetes.io/tls"`, eventType: api.EventType_ADD, obj: running, expected: false},
+		{name: "Missing field checked", expression: `!has(object.type)`, eventType: api.EventType_ADD, obj: running, expected: true},
+		{name: "No old object", expression: `oldObject == null`, eventType: api.EventType_ADD, obj: running, expected: true},
+		{
+			name:       "Tombstone",
+			expression: `object.metadata.name == "nginx"`,
+			eventType:  api.EventType_DELETE, obj: cache.DeletedFinalStateUnknown{Key: "default/nginx", Obj: running}, expected: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			filter, err := compileFilter(tc.expression)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			matched, _ := filter.matches(tc.eventType, tc.obj, tc.oldObj)
+			if matched != tc.expected {
+				t.Errorf("expected: %t, got: %t", tc.expected, matched)
+			}
+		})
+	}
+}
+
+func TestWatchFilter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := newTestWatchServer(ctrl, newTestPodInPhase("nginx", "Running"), newTestPodInPhase("redis", "Failed"))
+	events, cancel, done := startTestWatch(ctrl, s, &api.WatchRequest{
+		Version:   "v1",
+		Resource:  "pod",
+		Namespace: "default",
+		Filter:    `object.status.phase == "Failed"`,
+	})
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	waitForEventType(t, events, api.EventType_RESOLVED)
+	if event := waitForEventType(t, events, api.EventType_ADD); event.Name != "redis" {
+		t.Errorf("Expected only the failed pod, got %s", event.Name)
+	}
+	waitForEventType(t, events, api.EventType_SYNCED)
+
+	failed := newTestPodInPhase("nginx", "Failed")
+	failed.SetResourceVersion("2")
+	if _, err := s.dynamicClient.Resource(podsGVR).Namespace("default").Update(context.Background(), failed, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("Failed to update pod: %v", err)
+	}
+	if event := waitForEventType(t, events, api.EventType_UPDATE); event.Name != "nginx" {
+		t.Errorf("Expected the pod that failed, got %s", event.Name)
+	}
+}
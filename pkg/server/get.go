@@ -0,0 +1,75 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/cmwylie19/watch-informer/api"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Get returns a single object. Unless the request asks for a fresh read, it
+// comes from the cache of a running informer that watches every object of
+// the resource in its namespace, which also answers NotFound.
+func (s *server) Get(ctx context.Context, req *api.GetRequest) (*api.GetResponse, error) {
+	if s.dynamicClient == nil {
+		return nil, errClientNotInitialized
+	}
+	if req.Name == "" {
+		return nil, invalidArgument("name", "must not be empty")
+	}
+	spec, err := s.readSpec(&api.WatchRequest{
+		Group:     req.Group,
+		Version:   req.Version,
+		Resource:  req.Resource,
+		Namespace: req.Namespace,
+	})
+	if err != nil {
+		s.Logger.Error(fmt.Sprintf("Invalid get request: %v", err))
+		return nil, err
+	}
+
+	if !req.Fresh {
+		if si := s.cachedInformer(spec); si != nil {
+			s.Logger.Debug(fmt.Sprintf("Getting %s from the cache of %s", req.Name, spec.key()))
+			return getCached(si, spec.namespace, req.Name)
+		}
+	}
+	obj, err := s.dynamicClient.Resource(spec.gvr).Namespace(spec.namespace).Get(ctx, req.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, apiError(err)
+	}
+	return newGetResponse(obj, false)
+}
+
+func getCached(si *sharedInformer, namespace, name string) (*api.GetResponse, error) {
+	key := name
+	if namespace != "" {
+		key = namespace + "/" + name
+	}
+	obj, exists, err := si.informer.GetIndexer().GetByKey(key)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if !exists {
+		return nil, status.Errorf(codes.NotFound, "%s not found in the cache", key)
+	}
+	return newGetResponse(obj, true)
+}
+
+func newGetResponse(obj interface{}, cached bool) (*api.GetResponse, error) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	resp := &api.GetResponse{Object: data, Cached: cached}
+	if accessor, err := meta.Accessor(obj); err == nil {
+		resp.ResourceVersion = accessor.GetResourceVersion()
+	}
+	return resp, nil
+}
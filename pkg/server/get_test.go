@@ -0,0 +1,80 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/cmwylie19/watch-informer/api"
+)
+
+func TestGet(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := newTestWatchServer(ctrl, newTestPod("default", "nginx"))
+	get := func(req *api.GetRequest) *api.GetResponse {
+		t.Helper()
+		resp, err := s.Get(context.Background(), req)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		return resp
+	}
+	nginx := &api.GetRequest{Version: "v1", Resource: "pod", Namespace: "default", Name: "nginx"}
+
+	if resp := get(nginx); resp.Cached || resp.ResourceVersion != "1" {
+		t.Errorf("Expected a direct read at resourceVersion 1, got cached=%t at %s", resp.Cached, resp.ResourceVersion)
+	}
+	_, err := s.Get(context.Background(), &api.GetRequest{Version: "v1", Resource: "pod", Namespace: "default", Name: "redis"})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("Expected NotFound, got %v", err)
+	}
+
+	// An informer with a selector may not hold the object, so it is not used
+	filteredEvents, cancelFiltered, filteredDone := startTestWatch(ctrl, s, &api.WatchRequest{Version: "v1", Resource: "pod", LabelSelector: "app=nginx"})
+	waitForEventType(t, filteredEvents, api.EventType_RESOLVED)
+	waitForEventType(t, filteredEvents, api.EventType_SYNCED)
+	if resp := get(nginx); resp.Cached {
+		t.Errorf("Expected a direct read with only a filtered informer")
+	}
+	cancelFiltered()
+	<-filteredDone
+
+	events, cancel, done := startTestWatch(ctrl, s, &api.WatchRequest{Version: "v1", Resource: "pod"})
+	defer func() {
+		cancel()
+		<-done
+	}()
+	waitForEventType(t, events, api.EventType_RESOLVED)
+	waitForEventType(t, events, api.EventType_ADD)
+	waitForEventType(t, events, api.EventType_SYNCED)
+
+	if resp := get(nginx); !resp.Cached {
+		t.Errorf("Expected a cached read")
+	}
+	nginx.Fresh = true
+	if resp := get(nginx); resp.Cached {
+		t.Errorf("Expected a fresh read from the API server")
+	}
+	_, err = s.Get(context.Background(), &api.GetRequest{Version: "v1", Resource: "pod", Namespace: "default", Name: "redis"})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("Expected NotFound from the cache, got %v", err)
+	}
+}
+
+func TestGetInvalidRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := newTestWatchServer(ctrl)
+	_, err := s.Get(context.Background(), &api.GetRequest{Version: "v1", Resource: "pod", Namespace: "default"})
+	st := status.Convert(err)
+	if st.Code() != codes.InvalidArgument {
+		t.Fatalf("Expected InvalidArgument, got %v", err)
+	}
+	assertStatusDetails(t, st, "name", "")
+}
@@ -0,0 +1,332 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/cmwylie19/watch-informer/api"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/client-go/tools/cache"
+)
+
+const historySize = 1000
+
+var errResourceVersionExpired = errors.New("resource version is too old, relist required")
+
+// historyEvent is an informer event recorded for replay.
+type historyEvent struct {
+	eventType       api.EventType
+	obj             interface{}
+	oldObj          interface{}
+	resourceVersion uint64
+}
+
+// eventHistory keeps a bounded log of the events seen by a shared informer
+// after its initial list, so clients can resume from a resourceVersion.
+// Resumed subscribers follow the history directly, which keeps the replay
+// and the live events that follow it in order.
+type eventHistory struct {
+	mu        sync.Mutex
+	events    []historyEvent
+	size      int
+	floor     uint64
+	listed    uint64
+	applied   uint64
+	ready     chan struct{}
+	readyOnce sync.Once
+	followers map[*historyFollower]struct{}
+}
+
+// historyFollower hands the events of a history to a handler from its own
+// goroutine, like the informer does for its handlers, so a handler blocked
+// on a full queue holds up neither the history nor the informer. A follower
+// that falls further behind than the history reaches is stopped as expired.
+type historyFollower struct {
+	history *eventHistory
+	handler cache.ResourceEventHandler
+	// pending and expired are guarded by the history's mu.
+	pending []historyEvent
+	expired bool
+	added   chan struct{}
+	stopCh  chan struct{}
+	// replayed is closed once the events before the follow are delivered.
+	replayed chan struct{}
+}
+
+func newEventHistory(size int) *eventHistory {
+	return &eventHistory{
+		size:      size,
+		ready:     make(chan struct{}),
+		followers: make(map[*historyFollower]struct{}),
+	}
+}
+
+// handler returns the informer event handler that feeds the history.
+func (h *eventHistory) handler() cache.ResourceEventHandler {
+	return cache.ResourceEventHandlerDetailedFuncs{
+		AddFunc: func(obj interface{}, isInInitialList bool) {
+			if isInInitialList {
+				h.apply(objectResourceVersion(obj))
+				return
+			}
+			h.record(historyEvent{eventType: api.EventType_ADD, obj: obj})
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			if !resourceVersionChanged(oldObj, newObj) {
+				return
+			}
+			h.record(historyEvent{eventType: api.EventType_UPDATE, obj: newObj, oldObj: oldObj})
+		},
+		DeleteFunc: func(obj interface{}) {
+			h.record(historyEvent{eventType: api.EventType_DELETE, obj: obj})
+		},
+	}
+}
+
+// setFloor marks the history as complete for every resourceVersion at or
+// after resourceVersion. It is called whenever the informer lists.
+func (h *eventHistory) setFloor(resourceVersion string) {
+	rv, _ := strconv.ParseUint(resourceVersion, 10, 64)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if rv > h.floor {
+		h.floor = rv
+	}
+	if rv > h.listed {
+		h.listed = rv
+	}
+	h.readyOnce.Do(func() {
+		// The objects of a list can all be older than the list itself. Its
+		// version is only read once the history has seen the initial list,
+		// which the cache then holds in full.
+		if rv > h.applied {
+			h.applied = rv
+		}
+		close(h.ready)
+	})
+}
+
+// resumeVersion returns the oldest resourceVersion the history can resume
+// from, which is always safe to hand to clients as a bookmark.
+func (h *eventHistory) resumeVersion() string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.floor == 0 {
+		return ""
+	}
+	return strconv.FormatUint(h.floor, 10)
+}
+
+// apply notes that the informer's cache includes resourceVersion.
+func (h *eventHistory) apply(resourceVersion uint64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if resourceVersion > h.applied {
+		h.applied = resourceVersion
+	}
+}
+
+// appliedVersion returns the newest resourceVersion handed to the history,
+// starting with that of its initial list.
+// The informer updates its cache before it calls handlers, so the cache
+// includes every change up to it, unlike the informer's
+// LastSyncResourceVersion, which can be ahead of the cache.
+func (h *eventHistory) appliedVersion() string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	applied := h.applied
+	if applied == 0 {
+		applied = h.floor
+	}
+	if applied == 0 {
+		return ""
+	}
+	return strconv.FormatUint(applied, 10)
+}
+
+func (h *eventHistory) record(event historyEvent) {
+	event.resourceVersion = objectResourceVersion(event.obj)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	// A delete noticed by a relist carries the last resourceVersion seen of
+	// the object, but happened before the list
+	if _, finalStateUnknown := unwrapTombstone(event.obj); finalStateUnknown {
+		event.resourceVersion = h.listed
+	}
+
+	if event.resourceVersion > h.applied {
+		h.applied = event.resourceVersion
+	}
+
+	if len(h.events) == h.size {
+		if evicted := h.events[0].resourceVersion; evicted > h.floor {
+			h.floor = evicted
+		}
+		h.events = h.events[1:]
+	}
+	h.events = append(h.events, event)
+
+	for f := range h.followers {
+		if len(f.pending) == h.size {
+			f.expire()
+			continue
+		}
+		f.pending = append(f.pending, event)
+		f.signal()
+	}
+}
+
+// follow replays every recorded event after resourceVersion to handler and
+// then keeps delivering new events until the follower is stopped. It fails
+// with errResourceVersionExpired when the history no longer covers
+// resourceVersion.
+func (h *eventHistory) follow(resourceVersion string, handler cache.ResourceEventHandler) (*historyFollower, error) {
+	rv, err := parseResourceVersion(resourceVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if rv < h.floor {
+		return nil, errResourceVersionExpired
+	}
+	f := &historyFollower{
+		history:  h,
+		handler:  handler,
+		added:    make(chan struct{}, 1),
+		stopCh:   make(chan struct{}),
+		replayed: make(chan struct{}),
+	}
+	for _, event := range h.events {
+		if event.resourceVersion > rv {
+			f.pending = append(f.pending, event)
+		}
+	}
+	h.followers[f] = struct{}{}
+	go f.run(len(f.pending))
+	return f, nil
+}
+
+// run delivers the pending events until the follower is stopped. The first
+// replay events are the ones recorded before the follow.
+func (f *historyFollower) run(replay int) {
+	if replay == 0 {
+		close(f.replayed)
+	}
+	for {
+		f.history.mu.Lock()
+		events := f.pending
+		f.pending = nil
+		f.history.mu.Unlock()
+
+		for _, event := range events {
+			select {
+			case <-f.stopCh:
+				return
+			default:
+			}
+			deliverHistoryEvent(f.handler, event)
+			if replay > 0 {
+				if replay--; replay == 0 {
+					close(f.replayed)
+				}
+			}
+		}
+
+		select {
+		case <-f.added:
+		case <-f.stopCh:
+			return
+		}
+	}
+}
+
+func (f *historyFollower) signal() {
+	select {
+	case f.added <- struct{}{}:
+	default:
+	}
+}
+
+// hasReplayed reports whether the events before the follow have been
+// delivered.
+func (f *historyFollower) hasReplayed() bool {
+	select {
+	case <-f.replayed:
+		return true
+	default:
+		return false
+	}
+}
+
+// stop ends the delivery of events. It is safe to call more than once.
+func (f *historyFollower) stop() {
+	f.history.mu.Lock()
+	defer f.history.mu.Unlock()
+	if _, ok := f.history.followers[f]; !ok {
+		return
+	}
+	delete(f.history.followers, f)
+	close(f.stopCh)
+}
+
+// expire stops a follower that fell too far behind. The caller holds the
+// history's mu.
+func (f *historyFollower) expire() {
+	delete(f.history.followers, f)
+	f.pending = nil
+	f.expired = true
+	close(f.stopCh)
+}
+
+// stopped is closed once the follower no longer delivers events.
+func (f *historyFollower) stopped() <-chan struct{} {
+	return f.stopCh
+}
+
+// hasExpired reports whether the follower was stopped for falling behind.
+func (f *historyFollower) hasExpired() bool {
+	f.history.mu.Lock()
+	defer f.history.mu.Unlock()
+	return f.expired
+}
+
+// parseResourceVersion parses a resourceVersion handed out by the server.
+func parseResourceVersion(resourceVersion string) (uint64, error) {
+	rv, err := strconv.ParseUint(resourceVersion, 10, 64)
+	if err != nil {
+		return 0, invalidArgument("resource_version", fmt.Sprintf("%q is not a resourceVersion sent by the server", resourceVersion))
+	}
+	return rv, nil
+}
+
+func deliverHistoryEvent(handler cache.ResourceEventHandler, event historyEvent) {
+	switch event.eventType {
+	case api.EventType_ADD:
+		handler.OnAdd(event.obj, false)
+	case api.EventType_UPDATE:
+		handler.OnUpdate(event.oldObj, event.obj)
+	case api.EventType_DELETE:
+		handler.OnDelete(event.obj)
+	}
+}
+
+// objectResourceVersion returns the numeric resourceVersion of obj, or 0 when
+// it has none.
+func objectResourceVersion(obj interface{}) uint64 {
+	obj, _ = unwrapTombstone(obj)
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return 0
+	}
+	rv, _ := strconv.ParseUint(accessor.GetResourceVersion(), 10, 64)
+	return rv
+}
@@ -0,0 +1,229 @@
+package server
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cmwylie19/watch-informer/api"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/client-go/tools/cache"
+)
+
+// recordingHandler collects the events delivered to it as "TYPE/name@rv".
+type recordingHandler struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (r *recordingHandler) record(eventType api.EventType, obj interface{}) {
+	pod := obj.(interface {
+		GetName() string
+		GetResourceVersion() string
+	})
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, eventType.String()+"/"+pod.GetName()+"@"+pod.GetResourceVersion())
+}
+
+// waitForEvents waits until n events have been delivered and returns them.
+func (r *recordingHandler) waitForEvents(t *testing.T, n int) []string {
+	t.Helper()
+	waitForCondition(t, func() bool {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		return len(r.events) >= n
+	})
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.events...)
+}
+
+func (r *recordingHandler) handler() cache.ResourceEventHandler {
+	return cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { r.record(api.EventType_ADD, obj) },
+		UpdateFunc: func(_, obj interface{}) { r.record(api.EventType_UPDATE, obj) },
+		DeleteFunc: func(obj interface{}) { r.record(api.EventType_DELETE, obj) },
+	}
+}
+
+func newTestPodAt(name, resourceVersion string) interface{} {
+	pod := newTestPod("default", name)
+	pod.SetResourceVersion(resourceVersion)
+	return pod
+}
+
+func TestEventHistoryFollow(t *testing.T) {
+	history := newEventHistory(10)
+	history.setFloor("10")
+
+	recorder := history.handler()
+	recorder.OnAdd(newTestPodAt("a", "100"), true)
+	recorder.OnAdd(newTestPodAt("b", "11"), false)
+	recorder.OnUpdate(newTestPodAt("b", "11"), newTestPodAt("b", "12"))
+	recorder.OnUpdate(newTestPodAt("b", "12"), newTestPodAt("b", "12"))
+	recorder.OnDelete(newTestPodAt("a", "13"))
+
+	follower := &recordingHandler{}
+	f, err := history.follow("11", follower.handler())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	waitForCondition(t, f.hasReplayed)
+
+	recorder.OnAdd(newTestPodAt("c", "14"), false)
+	follower.waitForEvents(t, 3)
+	f.stop()
+	f.stop()
+	recorder.OnAdd(newTestPodAt("d", "15"), false)
+
+	assertEvents(t, []string{"UPDATE/b@12", "DELETE/a@13", "ADD/c@14"}, follower.waitForEvents(t, 3))
+}
+
+func TestEventHistorySlowFollower(t *testing.T) {
+	history := newEventHistory(10)
+	history.setFloor("10")
+	recorder := history.handler()
+	recorder.OnAdd(newTestPodAt("a", "11"), false)
+
+	// The first follower is stuck on its first event.
+	blocked := make(chan struct{})
+	defer close(blocked)
+	slow, err := history.follow("10", cache.ResourceEventHandlerFuncs{
+		AddFunc: func(interface{}) { <-blocked },
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer slow.stop()
+
+	finished := make(chan struct{})
+	follower := &recordingHandler{}
+	go func() {
+		defer close(finished)
+		recorder.OnAdd(newTestPodAt("b", "12"), false)
+		history.setFloor("11")
+		f, err := history.follow("11", follower.handler())
+		if err != nil {
+			t.Errorf("Unexpected error: %v", err)
+			return
+		}
+		defer f.stop()
+		follower.waitForEvents(t, 1)
+	}()
+
+	select {
+	case <-finished:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Expected a blocked follower not to hold up the history")
+	}
+	if slow.hasReplayed() {
+		t.Error("Expected the blocked follower not to have replayed the history")
+	}
+}
+
+func TestEventHistoryFollowerFallsBehind(t *testing.T) {
+	history := newEventHistory(3)
+	history.setFloor("10")
+	recorder := history.handler()
+
+	delivering := make(chan struct{}, 1)
+	blocked := make(chan struct{})
+	defer close(blocked)
+	f, err := history.follow("10", cache.ResourceEventHandlerFuncs{
+		AddFunc: func(interface{}) {
+			delivering <- struct{}{}
+			<-blocked
+		},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer f.stop()
+
+	// The first event is stuck in the handler, the next three are pending
+	// and a fifth is more than the history holds.
+	recorder.OnAdd(newTestPodAt("a", "11"), false)
+	<-delivering
+	for i, name := range []string{"b", "c", "d"} {
+		recorder.OnAdd(newTestPodAt(name, strconv.Itoa(12+i)), false)
+	}
+	if f.hasExpired() {
+		t.Fatal("Expected the follower to keep up with three pending events")
+	}
+	recorder.OnAdd(newTestPodAt("e", "15"), false)
+
+	select {
+	case <-f.stopped():
+	default:
+		t.Fatal("Expected the follower to be stopped")
+	}
+	if !f.hasExpired() {
+		t.Error("Expected the follower to have expired")
+	}
+}
+
+func TestEventHistoryRelistDelete(t *testing.T) {
+	history := newEventHistory(10)
+	history.setFloor("10")
+	recorder := history.handler()
+	recorder.OnAdd(newTestPodAt("a", "11"), false)
+
+	// The relist no longer has a, which was last seen at 11
+	history.setFloor("20")
+	recorder.OnDelete(cache.DeletedFinalStateUnknown{Key: "default/a", Obj: newTestPodAt("a", "11")})
+
+	history.mu.Lock()
+	defer history.mu.Unlock()
+	if rv := history.events[1].resourceVersion; rv != 20 {
+		t.Errorf("Expected the delete at the relist's resourceVersion 20, got %d", rv)
+	}
+}
+
+func TestEventHistoryFollowExpired(t *testing.T) {
+	history := newEventHistory(2)
+	history.setFloor("10")
+
+	recorder := history.handler()
+	recorder.OnAdd(newTestPodAt("a", "11"), false)
+	recorder.OnAdd(newTestPodAt("b", "12"), false)
+
+	tests := []struct {
+		name            string
+		resourceVersion string
+		wantErr         bool
+	}{
+		{name: "At floor", resourceVersion: "10"},
+		{name: "Before floor", resourceVersion: "9", wantErr: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			f, err := history.follow(tc.resourceVersion, (&recordingHandler{}).handler())
+			if errors.Is(err, errResourceVersionExpired) != tc.wantErr {
+				t.Errorf("expected expired: %v, got error: %v", tc.wantErr, err)
+			}
+			if err == nil {
+				f.stop()
+			}
+		})
+	}
+
+	if _, err := history.follow("abc", (&recordingHandler{}).handler()); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected a resourceVersion that is not a number to be invalid, got: %v", err)
+	}
+
+	// Evicting the oldest event moves the floor up to it.
+	recorder.OnAdd(newTestPodAt("c", "13"), false)
+	if _, err := history.follow("10", (&recordingHandler{}).handler()); !errors.Is(err, errResourceVersionExpired) {
+		t.Errorf("Expected resume before an evicted event to be expired, got: %v", err)
+	}
+	f, err := history.follow("11", (&recordingHandler{}).handler())
+	if err != nil {
+		t.Fatalf("Expected resume from the evicted event to succeed, got: %v", err)
+	}
+	f.stop()
+}
@@ -0,0 +1,320 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/cmwylie19/watch-informer/api"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/metadata"
+	"k8s.io/client-go/tools/cache"
+)
+
+// DefaultResyncPeriod is how often informers hand every cached object to the
+// subscriptions that asked for resyncs, unless the server is given another
+// period.
+const DefaultResyncPeriod = 5 * time.Minute
+
+// informerIdleTimeout is how long an informer keeps running after its last
+// subscriber has gone, so a client that reconnects, such as a restarting
+// controller, can still resume from its history.
+const informerIdleTimeout = 5 * time.Minute
+
+// sharedInformer is a running informer shared by every Watch stream with the
+// same key. Subscribers attach their own event handlers to it.
+type sharedInformer struct {
+	informer cache.SharedIndexInformer
+	history  *eventHistory
+	// historySynced reports whether the history has seen the initial list.
+	historySynced func() bool
+	stopCh        chan struct{}
+	refs          int
+	// idle stops the informer once it has had no subscribers for a while.
+	idle *time.Timer
+
+	errMu sync.Mutex
+	// listErr is why the last list or watch failed, until a list succeeds.
+	listErr error
+}
+
+// informerSpec describes what a shared informer lists and watches.
+type informerSpec struct {
+	gvr           schema.GroupVersionResource
+	namespace     string
+	labelSelector string
+	fieldSelector string
+	metadataOnly  bool
+}
+
+// key identifies the informer for spec in the registry.
+func (spec informerSpec) key() string {
+	return formatSessionID(&api.WatchRequest{
+		Group:         spec.gvr.Group,
+		Version:       spec.gvr.Version,
+		Resource:      spec.gvr.Resource,
+		Namespace:     spec.namespace,
+		LabelSelector: spec.labelSelector,
+		FieldSelector: spec.fieldSelector,
+		MetadataOnly:  spec.metadataOnly,
+	})
+}
+
+// informerRegistry hands out reference-counted informers keyed by the tuple
+// formatSessionID computes, so concurrent streams for the same GVR, namespace
+// and selectors share a single LIST and WATCH against the API server.
+type informerRegistry struct {
+	dynamicClient  dynamic.Interface
+	metadataClient metadata.Interface
+	resyncPeriod   time.Duration
+	idleTimeout    time.Duration
+	mu             sync.Mutex
+	informers      map[string]*sharedInformer
+}
+
+// newInformerRegistry returns a registry whose informers resync every
+// resyncPeriod, or never when it is zero.
+func newInformerRegistry(dynamicClient dynamic.Interface, metadataClient metadata.Interface, resyncPeriod time.Duration) *informerRegistry {
+	return &informerRegistry{
+		dynamicClient:  dynamicClient,
+		metadataClient: metadataClient,
+		resyncPeriod:   resyncPeriod,
+		idleTimeout:    informerIdleTimeout,
+		informers:      make(map[string]*sharedInformer),
+	}
+}
+
+// acquire returns the informer for key, creating and starting it on first use.
+// Every successful call must be paired with a release of the same key.
+func (r *informerRegistry) acquire(key string, spec informerSpec) (*sharedInformer, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if si, ok := r.informers[key]; ok {
+		si.refs++
+		if si.idle != nil {
+			si.idle.Stop()
+			si.idle = nil
+		}
+		return si, nil
+	}
+
+	listWatch, objType, err := r.listWatch(spec)
+	if err != nil {
+		return nil, err
+	}
+	si := &sharedInformer{
+		history: newEventHistory(historySize),
+		stopCh:  make(chan struct{}),
+		refs:    1,
+	}
+
+	tweakListOptions := func(options *metav1.ListOptions) {
+		options.LabelSelector = spec.labelSelector
+		options.FieldSelector = spec.fieldSelector
+	}
+	si.informer = cache.NewSharedIndexInformer(
+		&cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				tweakListOptions(&options)
+				list, err := listWatch.ListFunc(options)
+				if err != nil {
+					return nil, err
+				}
+				si.setListError(nil)
+				listMeta, err := meta.ListAccessor(list)
+				if err != nil {
+					return nil, err
+				}
+				// Events before a (re)list are not in the history, so it
+				// only covers resume points from the list onwards.
+				si.history.setFloor(listMeta.GetResourceVersion())
+				return list, nil
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				tweakListOptions(&options)
+				return listWatch.WatchFunc(options)
+			},
+		},
+		objType,
+		r.resyncPeriod,
+		cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc},
+	)
+
+	// The history handler is registered before the informer starts so it
+	// sees every event after the initial list.
+	registration, err := si.informer.AddEventHandlerWithResyncPeriod(si.history.handler(), 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to register history handler: %w", err)
+	}
+	si.historySynced = registration.HasSynced
+	// Subscriptions waiting for the initial list give up when it fails
+	err = si.informer.SetWatchErrorHandler(func(r *cache.Reflector, err error) {
+		si.setListError(err)
+		cache.DefaultWatchErrorHandler(r, err)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to set watch error handler: %w", err)
+	}
+	r.informers[key] = si
+	go si.informer.Run(si.stopCh)
+	return si, nil
+}
+
+// listWatch lists and watches the objects of spec, and returns the type of
+// object the informer caches. Metadata-only informers get their objects from
+// the metadata client, so they only cache PartialObjectMetadata.
+func (r *informerRegistry) listWatch(spec informerSpec) (*cache.ListWatch, runtime.Object, error) {
+	if !spec.metadataOnly {
+		resource := r.dynamicClient.Resource(spec.gvr).Namespace(spec.namespace)
+		return &cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				return resource.List(context.TODO(), options)
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				return resource.Watch(context.TODO(), options)
+			},
+		}, &unstructured.Unstructured{}, nil
+	}
+
+	if r.metadataClient == nil {
+		return nil, nil, errors.New("metadata client is not configured")
+	}
+	resource := r.metadataClient.Resource(spec.gvr).Namespace(spec.namespace)
+	return &cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			return resource.List(context.TODO(), options)
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			return resource.Watch(context.TODO(), options)
+		},
+	}, &metav1.PartialObjectMetadata{}, nil
+}
+
+// get returns the running informer for key, without taking a reference.
+func (r *informerRegistry) get(key string) (*sharedInformer, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	si, ok := r.informers[key]
+	return si, ok
+}
+
+// handlerRegistration is a handler attached to a shared informer.
+type handlerRegistration struct {
+	// hasSynced reports whether the handler has received the initial list,
+	// or the replayed history when resuming.
+	hasSynced func() bool
+	remove    func() error
+	// stopped is closed when a resumed handler stops receiving events, and
+	// expired then reports whether it fell too far behind the history. Both
+	// are nil for handlers on the informer itself.
+	stopped <-chan struct{}
+	expired func() bool
+}
+
+// subscribe attaches handler to the informer. Without a resourceVersion the
+// handler first receives the current list as ADD events, and then every
+// object again each resyncPeriod unless it is zero. With one, it receives
+// only the recorded events after that resourceVersion, or
+// errResourceVersionExpired if they are no longer known.
+func (si *sharedInformer) subscribe(ctx context.Context, handler cache.ResourceEventHandler, resourceVersion string, resyncPeriod time.Duration) (*handlerRegistration, error) {
+	if resourceVersion == "" {
+		registration, err := si.informer.AddEventHandlerWithResyncPeriod(handler, resyncPeriod)
+		if err != nil {
+			return nil, fmt.Errorf("failed to register event handler: %w", err)
+		}
+		return &handlerRegistration{
+			hasSynced: registration.HasSynced,
+			remove: func() error {
+				return si.informer.RemoveEventHandler(registration)
+			},
+		}, nil
+	}
+
+	select {
+	case <-si.history.ready:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	follower, err := si.history.follow(resourceVersion, handler)
+	if err != nil {
+		return nil, err
+	}
+	return &handlerRegistration{
+		hasSynced: follower.hasReplayed,
+		remove: func() error {
+			follower.stop()
+			return nil
+		},
+		stopped: follower.stopped(),
+		expired: follower.hasExpired,
+	}, nil
+}
+
+func (si *sharedInformer) setListError(err error) {
+	si.errMu.Lock()
+	defer si.errMu.Unlock()
+	si.listErr = err
+}
+
+// listError returns why the informer failed to list or watch since its last
+// successful list, or nil.
+func (si *sharedInformer) listError() error {
+	si.errMu.Lock()
+	defer si.errMu.Unlock()
+	return si.listErr
+}
+
+// release drops one reference to key. The informer, and with it the history
+// clients resume from, is stopped once it has had no subscribers for the
+// idle timeout.
+func (r *informerRegistry) release(key string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	si, ok := r.informers[key]
+	if !ok {
+		return
+	}
+	si.refs--
+	if si.refs > 0 {
+		return
+	}
+	// An informer that never listed has no history to resume from
+	if r.idleTimeout == 0 || !si.informer.HasSynced() {
+		r.stop(key, si)
+		return
+	}
+	var idle *time.Timer
+	idle = time.AfterFunc(r.idleTimeout, func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		// Acquiring the informer again stops the timer, but it may already
+		// be waiting for the lock
+		if si.idle == idle {
+			r.stop(key, si)
+		}
+	})
+	si.idle = idle
+}
+
+func (r *informerRegistry) stop(key string, si *sharedInformer) {
+	close(si.stopCh)
+	delete(r.informers, key)
+}
+
+// len returns the number of running informers, including idle ones.
+func (r *informerRegistry) len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.informers)
+}
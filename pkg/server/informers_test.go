@@ -0,0 +1,218 @@
+package server
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/fake"
+	metadatafake "k8s.io/client-go/metadata/fake"
+	clienttesting "k8s.io/client-go/testing"
+
+	"github.com/cmwylie19/watch-informer/api"
+)
+
+var (
+	podsGVR        = schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+	deploymentsGVR = schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+)
+
+func newFakeDynamicClient(objects ...runtime.Object) *fake.FakeDynamicClient {
+	return fake.NewSimpleDynamicClientWithCustomListKinds(
+		runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{
+			podsGVR:        "PodList",
+			deploymentsGVR: "DeploymentList",
+			namespacesGVR:  "NamespaceList",
+			crdsGVR:        "CustomResourceDefinitionList",
+			apiServicesGVR: "APIServiceList",
+			widgetsGVR:     "WidgetList",
+		},
+		objects...,
+	)
+}
+
+// listAtResourceVersion makes the fake client list gvr at resourceVersion,
+// as the API server does, instead of at none.
+func listAtResourceVersion(client *fake.FakeDynamicClient, gvr schema.GroupVersionResource, resourceVersion string) {
+	client.PrependReactor("list", gvr.Resource, func(action clienttesting.Action) (bool, runtime.Object, error) {
+		_, list, err := clienttesting.ObjectReaction(client.Tracker())(action)
+		if err != nil {
+			return true, nil, err
+		}
+		listMeta, err := meta.ListAccessor(list)
+		if err != nil {
+			return true, nil, err
+		}
+		listMeta.SetResourceVersion(resourceVersion)
+		return true, list, nil
+	})
+}
+
+func mustAcquire(t *testing.T, registry *informerRegistry, key, namespace string) *sharedInformer {
+	t.Helper()
+	si, err := registry.acquire(key, informerSpec{gvr: podsGVR, namespace: namespace})
+	if err != nil {
+		t.Fatalf("Failed to acquire informer: %v", err)
+	}
+	return si
+}
+
+func TestInformerRegistrySharesInformers(t *testing.T) {
+	registry := newInformerRegistry(newFakeDynamicClient(), nil, DefaultResyncPeriod)
+	registry.idleTimeout = 0
+
+	first := mustAcquire(t, registry, "pods/default", "default")
+	second := mustAcquire(t, registry, "pods/default", "default")
+	other := mustAcquire(t, registry, "pods/other", "other")
+
+	if first != second {
+		t.Errorf("Expected the same informer for the same key")
+	}
+	if first == other {
+		t.Errorf("Expected a different informer for a different key")
+	}
+	if registry.len() != 2 {
+		t.Errorf("Expected 2 running informers, got %d", registry.len())
+	}
+
+	registry.release("pods/default")
+	select {
+	case <-first.stopCh:
+		t.Fatalf("Expected informer to keep running while it has subscribers")
+	default:
+	}
+
+	registry.release("pods/default")
+	select {
+	case <-first.stopCh:
+	default:
+		t.Fatalf("Expected informer to be stopped after the last release")
+	}
+
+	registry.release("pods/other")
+	if registry.len() != 0 {
+		t.Errorf("Expected no running informers, got %d", registry.len())
+	}
+}
+
+func TestInformerRegistryKeepsIdleInformers(t *testing.T) {
+	registry := newInformerRegistry(newFakeDynamicClient(), nil, DefaultResyncPeriod)
+	registry.idleTimeout = 100 * time.Millisecond
+
+	first := mustAcquire(t, registry, "pods/default", "default")
+	waitForCondition(t, first.informer.HasSynced)
+	registry.release("pods/default")
+	if again := mustAcquire(t, registry, "pods/default", "default"); again != first {
+		t.Errorf("Expected the idle informer to be reused")
+	}
+
+	// Reacquiring canceled the first idle timeout
+	time.Sleep(200 * time.Millisecond)
+	select {
+	case <-first.stopCh:
+		t.Fatalf("Expected the informer to keep running while it has subscribers")
+	default:
+	}
+
+	registry.release("pods/default")
+	waitForCondition(t, func() bool { return registry.len() == 0 })
+	select {
+	case <-first.stopCh:
+	default:
+		t.Errorf("Expected the informer to be stopped after the idle timeout")
+	}
+}
+
+func TestInformerRegistryReleaseUnknownKey(t *testing.T) {
+	registry := newInformerRegistry(newFakeDynamicClient(), nil, DefaultResyncPeriod)
+	registry.release("missing")
+
+	if registry.len() != 0 {
+		t.Errorf("Expected no running informers, got %d", registry.len())
+	}
+}
+
+// newFakeMetadataClient serves objects as the metadata of gvr. Like those of
+// the API server, they do not say what kind of object they are.
+func newFakeMetadataClient(gvr schema.GroupVersionResource, objects ...*metav1.PartialObjectMetadata) *metadatafake.FakeMetadataClient {
+	scheme := runtime.NewScheme()
+	if err := metav1.AddMetaToScheme(scheme); err != nil {
+		panic(err)
+	}
+	client := metadatafake.NewSimpleMetadataClient(scheme)
+	for _, obj := range objects {
+		if err := client.Tracker().Create(gvr, obj, obj.Namespace); err != nil {
+			panic(err)
+		}
+	}
+	return client
+}
+
+func newTestPodMetadata(namespace, name string) *metav1.PartialObjectMetadata {
+	return &metav1.PartialObjectMetadata{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       namespace,
+			Name:            name,
+			ResourceVersion: "1",
+			Labels:          map[string]string{"app": name},
+		},
+	}
+}
+
+func TestInformerRegistryMetadataOnly(t *testing.T) {
+	registry := newInformerRegistry(newFakeDynamicClient(), nil, DefaultResyncPeriod)
+	spec := informerSpec{gvr: podsGVR, metadataOnly: true}
+	if _, err := registry.acquire(spec.key(), spec); err == nil {
+		t.Error("Expected an error without a metadata client")
+	}
+	if spec.key() == (informerSpec{gvr: podsGVR}).key() {
+		t.Error("Expected metadata-only informers to have their own key")
+	}
+}
+
+func TestWatchMetadataOnly(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := newTestWatchServer(ctrl, newTestPodInPhase("nginx", "Running"))
+	s.resolver = newDiscoveryResolver(newTestDiscovery())
+	metadataClient := newFakeMetadataClient(podsGVR, newTestPodMetadata("default", "nginx"))
+	s.informers.metadataClient = metadataClient
+	events, cancel, done := startTestWatch(ctrl, s, &api.WatchRequest{
+		Version:      "v1",
+		Resource:     "pod",
+		Namespace:    "default",
+		MetadataOnly: true,
+		Filter:       `object.metadata.labels.app == "nginx"`,
+	})
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	waitForEventType(t, events, api.EventType_RESOLVED)
+	event := waitForEventType(t, events, api.EventType_ADD)
+	if event.Name != "nginx" || event.ResourceVersion != "1" {
+		t.Errorf("Expected the pod, got %s at %s", event.Name, event.ResourceVersion)
+	}
+	if event.ApiVersion != "v1" || event.Kind != "Pod" {
+		t.Errorf("Expected v1/Pod, got %s/%s", event.ApiVersion, event.Kind)
+	}
+	if strings.Contains(string(event.Object), "status") {
+		t.Errorf("Expected only the metadata of the pod, got %s", event.Object)
+	}
+	waitForEventType(t, events, api.EventType_SYNCED)
+
+	if actions := s.dynamicClient.(*fake.FakeDynamicClient).Actions(); len(actions) > 0 {
+		t.Errorf("Expected no requests for whole objects, got %v", actions)
+	}
+	if len(metadataClient.Actions()) == 0 {
+		t.Error("Expected the metadata client to list the pods")
+	}
+}
@@ -0,0 +1,292 @@
+package server
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cmwylie19/watch-informer/api"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/client-go/tools/cache"
+)
+
+// continueVersion tells the continues of this server apart from any other.
+const continueVersion = "watch-informer/v2"
+
+// listSnapshotRetention is how long the later pages of a list can be asked
+// for after its first page.
+const listSnapshotRetention = 5 * time.Minute
+
+// continueToken is the continue of a page. The next page starts at offset
+// in the snapshot taken for the first page.
+type continueToken struct {
+	Version  string `json:"v"`
+	Snapshot uint64 `json:"snapshot"`
+	Offset   int    `json:"offset"`
+}
+
+func encodeContinue(snapshot uint64, offset int) string {
+	data, _ := json.Marshal(continueToken{Version: continueVersion, Snapshot: snapshot, Offset: offset})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeContinue returns the token of a continue issued by this server, or
+// nil for any other continue.
+func decodeContinue(value string) *continueToken {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil
+	}
+	var token continueToken
+	if err := json.Unmarshal(data, &token); err != nil || token.Version != continueVersion {
+		return nil
+	}
+	return &token
+}
+
+// listSnapshot is the cache of an informer as the first page of a list saw
+// it, sorted by key. The objects are shared with the cache, which never
+// modifies them.
+type listSnapshot struct {
+	key             string
+	resourceVersion string
+	objs            []interface{}
+
+	// Owned by listSnapshots, id is zero until the snapshot is kept
+	id     uint64
+	expiry *time.Timer
+}
+
+// listSnapshots keeps the snapshots of lists that have pages left, so every
+// page comes from the same state as the first.
+type listSnapshots struct {
+	mu        sync.Mutex
+	next      uint64
+	snapshots map[uint64]*listSnapshot
+	retention time.Duration
+}
+
+func newListSnapshots(retention time.Duration) *listSnapshots {
+	return &listSnapshots{
+		snapshots: make(map[uint64]*listSnapshot),
+		retention: retention,
+	}
+}
+
+// add keeps snapshot for the retention period and assigns it an id.
+func (r *listSnapshots) add(snapshot *listSnapshot) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.next++
+	id := r.next
+	snapshot.id = id
+	snapshot.expiry = time.AfterFunc(r.retention, func() { r.remove(id) })
+	r.snapshots[id] = snapshot
+}
+
+// get returns the snapshot with id, if it is still kept.
+func (r *listSnapshots) get(id uint64) (*listSnapshot, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	snapshot, ok := r.snapshots[id]
+	return snapshot, ok
+}
+
+// remove forgets the snapshot with id.
+func (r *listSnapshots) remove(id uint64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if snapshot, ok := r.snapshots[id]; ok {
+		snapshot.expiry.Stop()
+		delete(r.snapshots, id)
+	}
+}
+
+// len returns the number of kept snapshots.
+func (r *listSnapshots) len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.snapshots)
+}
+
+// List returns the objects of a resource from the cache of an informer. One
+// is started for the list when none is running, and like any other it is
+// kept for a while once released, so a Watch from the resourceVersion of the
+// list can resume from its history.
+func (s *server) List(ctx context.Context, req *api.ListRequest) (*api.ListResponse, error) {
+	if s.dynamicClient == nil {
+		return nil, errClientNotInitialized
+	}
+	if req.Limit < 0 {
+		return nil, invalidArgument("limit", "must not be negative")
+	}
+	spec, err := s.readSpec(&api.WatchRequest{
+		Group:         req.Group,
+		Version:       req.Version,
+		Resource:      req.Resource,
+		Namespace:     req.Namespace,
+		LabelSelector: req.LabelSelector,
+		FieldSelector: req.FieldSelector,
+	})
+	if err != nil {
+		s.Logger.Error(fmt.Sprintf("Invalid list request: %v", err))
+		return nil, err
+	}
+	if req.Continue != "" {
+		return s.listNext(spec, req)
+	}
+
+	si := s.cachedInformer(spec)
+	cached := si != nil
+	if !cached {
+		key := spec.key()
+		s.Logger.Debug(fmt.Sprintf("Starting an informer to list %s", key))
+		if si, err = s.informers.acquire(key, spec); err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		defer s.informers.release(key)
+		if err := s.waitForList(ctx, si, spec); err != nil {
+			return nil, err
+		}
+	}
+	snapshot, err := snapshotCache(si, spec)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.listPage(snapshot, 0, req.Limit)
+	if err != nil {
+		return nil, err
+	}
+	resp.Cached = cached
+	return resp, nil
+}
+
+// listNext returns the page whose continue req passes.
+func (s *server) listNext(spec informerSpec, req *api.ListRequest) (*api.ListResponse, error) {
+	token := decodeContinue(req.Continue)
+	if token == nil {
+		return nil, invalidArgument("continue", "not a continue returned by List")
+	}
+	snapshot, ok := s.listSnapshots.get(token.Snapshot)
+	if !ok {
+		return nil, status.Error(codes.Aborted, "the list has expired, start the list over")
+	}
+	if snapshot.key != spec.key() {
+		return nil, invalidArgument("continue", "belongs to a different list")
+	}
+	resp, err := s.listPage(snapshot, token.Offset, req.Limit)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Continue == "" {
+		s.listSnapshots.remove(snapshot.id)
+	}
+	resp.Cached = true
+	return resp, nil
+}
+
+// readSpec formats req and resolves it to the single resource a List or Get
+// reads.
+func (s *server) readSpec(req *api.WatchRequest) (informerSpec, error) {
+	formatted, resources, err := s.formatAndResolve(req)
+	if err != nil {
+		return informerSpec{}, err
+	}
+	if len(resources) != 1 {
+		return informerSpec{}, invalidArgument("resource", "must name a single resource, not a category")
+	}
+	return informerSpec{
+		gvr:           resources[0],
+		namespace:     formatted.Namespace,
+		labelSelector: formatted.LabelSelector,
+		fieldSelector: formatted.FieldSelector,
+	}, nil
+}
+
+// cachedInformer returns a synced informer holding every object spec
+// selects, which watches either its namespace or all namespaces.
+func (s *server) cachedInformer(spec informerSpec) *sharedInformer {
+	specs := []informerSpec{spec}
+	if spec.namespace != "" {
+		all := spec
+		all.namespace = ""
+		specs = append(specs, all)
+	}
+	for _, spec := range specs {
+		if si, ok := s.informers.get(spec.key()); ok && si.informer.HasSynced() && si.historySynced() {
+			return si
+		}
+	}
+	return nil
+}
+
+// snapshotCache takes a snapshot of the objects of si that spec selects.
+func snapshotCache(si *sharedInformer, spec informerSpec) (*listSnapshot, error) {
+	// Read before the objects, so watching from it can repeat changes that
+	// are already listed but never miss any
+	snapshot := &listSnapshot{key: spec.key(), resourceVersion: si.history.appliedVersion()}
+
+	if spec.namespace == "" {
+		snapshot.objs = si.informer.GetIndexer().List()
+	} else {
+		var err error
+		snapshot.objs, err = si.informer.GetIndexer().ByIndex(cache.NamespaceIndex, spec.namespace)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+	}
+	keys := make([]string, len(snapshot.objs))
+	for i, obj := range snapshot.objs {
+		keys[i], _ = cache.MetaNamespaceKeyFunc(obj)
+	}
+	sort.Sort(byKey{keys: keys, objs: snapshot.objs})
+	return snapshot, nil
+}
+
+// listPage returns at most limit objects of snapshot from offset on, keeping
+// the snapshot for the next page when there is one.
+func (s *server) listPage(snapshot *listSnapshot, offset int, limit int64) (*api.ListResponse, error) {
+	if offset < 0 || offset > len(snapshot.objs) {
+		return nil, invalidArgument("continue", "points outside of the list")
+	}
+	end := len(snapshot.objs)
+	if limit > 0 && int64(end-offset) > limit {
+		end = offset + int(limit)
+	}
+
+	resp := &api.ListResponse{ResourceVersion: snapshot.resourceVersion}
+	for _, obj := range snapshot.objs[offset:end] {
+		data, err := json.Marshal(obj)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		resp.Items = append(resp.Items, data)
+	}
+	if end < len(snapshot.objs) {
+		if snapshot.id == 0 {
+			s.listSnapshots.add(snapshot)
+		}
+		resp.Continue = encodeContinue(snapshot.id, end)
+	}
+	return resp, nil
+}
+
+// byKey sorts objects by their keys.
+type byKey struct {
+	keys []string
+	objs []interface{}
+}
+
+func (b byKey) Len() int           { return len(b.keys) }
+func (b byKey) Less(i, j int) bool { return b.keys[i] < b.keys[j] }
+func (b byKey) Swap(i, j int) {
+	b.keys[i], b.keys[j] = b.keys[j], b.keys[i]
+	b.objs[i], b.objs[j] = b.objs[j], b.objs[i]
+}
@@ -0,0 +1,292 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic/fake"
+	clienttesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/cmwylie19/watch-informer/api"
+)
+
+// itemNames returns namespace/name of every item in resp.
+func itemNames(t *testing.T, resp *api.ListResponse) []string {
+	t.Helper()
+	var names []string
+	for _, item := range resp.Items {
+		var obj unstructured.Unstructured
+		if err := json.Unmarshal(item, &obj.Object); err != nil {
+			t.Fatalf("Failed to decode item: %v", err)
+		}
+		names = append(names, obj.GetNamespace()+"/"+obj.GetName())
+	}
+	return names
+}
+
+func TestListStartsInformer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	matching := newTestPod("default", "nginx")
+	matching.SetLabels(map[string]string{"app": "nginx"})
+	s := newTestWatchServer(ctrl, matching, newTestPod("default", "redis"))
+	s.informers.idleTimeout = time.Minute
+	listAtResourceVersion(s.dynamicClient.(*fake.FakeDynamicClient), podsGVR, "10")
+
+	req := &api.ListRequest{Version: "v1", Resource: "pod", Namespace: "default", LabelSelector: "app=nginx"}
+	list, err := s.List(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if list.Cached || list.ResourceVersion != "10" {
+		t.Errorf("Expected a list at resourceVersion 10 from a new informer, got %v", list)
+	}
+	assertEvents(t, []string{"default/nginx"}, itemNames(t, list))
+
+	// The informer outlives the list, so a watch from it resumes
+	if resp, err := s.List(context.Background(), req); err != nil || !resp.Cached {
+		t.Errorf("Expected the next list to be served from the cache, got %v, %v", resp, err)
+	}
+	updated := newTestPod("default", "nginx")
+	updated.SetLabels(map[string]string{"app": "nginx"})
+	updated.SetResourceVersion("11")
+	if _, err := s.dynamicClient.Resource(podsGVR).Namespace("default").Update(context.Background(), updated, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("Failed to update pod: %v", err)
+	}
+	events, cancel, done := startTestWatch(ctrl, s, &api.WatchRequest{Version: "v1", Resource: "pod", Namespace: "default", LabelSelector: "app=nginx", ResourceVersion: list.ResourceVersion})
+	defer func() {
+		cancel()
+		<-done
+	}()
+	waitForEventType(t, events, api.EventType_RESOLVED)
+	event := waitForEvent(t, events)
+	if event.Type == api.EventType_SYNCED {
+		event = waitForEvent(t, events)
+	}
+	if event.Type != api.EventType_UPDATE || event.ResourceVersion != "11" {
+		t.Errorf("Expected the update after the list, got %s at %s", event.Type, event.ResourceVersion)
+	}
+}
+
+func TestListRefused(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := newTestWatchServer(ctrl)
+	s.dynamicClient.(*fake.FakeDynamicClient).PrependReactor("list", "pods", func(clienttesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewForbidden(podsGVR.GroupResource(), "", errors.New("not allowed"))
+	})
+
+	_, err := s.List(context.Background(), &api.ListRequest{Version: "v1", Resource: "pod", Namespace: "default"})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Errorf("Expected PermissionDenied, got %v", err)
+	}
+	waitForCondition(t, func() bool { return s.informers.len() == 0 })
+}
+
+func TestListCached(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := newTestWatchServer(ctrl, newTestPod("default", "redis"), newTestPod("default", "nginx"), newTestPod("other", "mysql"))
+	events, cancel, done := startTestWatch(ctrl, s, &api.WatchRequest{Version: "v1", Resource: "pod"})
+	defer func() {
+		cancel()
+		<-done
+	}()
+	waitForEventType(t, events, api.EventType_RESOLVED)
+	for i := 0; i < 3; i++ {
+		waitForEventType(t, events, api.EventType_ADD)
+	}
+	waitForEventType(t, events, api.EventType_SYNCED)
+
+	// The informer for all namespaces serves a single one through its index
+	req := &api.ListRequest{Version: "v1", Resource: "pod", Namespace: "default", Limit: 1}
+	var names []string
+	for page := 0; page < 3; page++ {
+		resp, err := s.List(context.Background(), req)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !resp.Cached {
+			t.Fatalf("Expected the list to be served from the cache")
+		}
+		names = append(names, itemNames(t, resp)...)
+		if resp.Continue == "" {
+			break
+		}
+		req.Continue = resp.Continue
+	}
+	assertEvents(t, []string{"default/nginx", "default/redis"}, names)
+
+	// Later pages come from the cache as the first page saw it
+	first, err := s.List(context.Background(), &api.ListRequest{Version: "v1", Resource: "pod", Limit: 2})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := s.dynamicClient.Resource(podsGVR).Namespace("default").Delete(context.Background(), "nginx", metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("Failed to delete pod: %v", err)
+	}
+	waitForEventType(t, events, api.EventType_DELETE)
+	last, err := s.List(context.Background(), &api.ListRequest{Version: "v1", Resource: "pod", Limit: 2, Continue: first.Continue})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if last.Continue != "" || last.ResourceVersion != first.ResourceVersion {
+		t.Errorf("Expected the last page at resourceVersion %s, got %v", first.ResourceVersion, last)
+	}
+	assertEvents(t, []string{"default/nginx", "default/redis", "other/mysql"}, append(itemNames(t, first), itemNames(t, last)...))
+	if s.listSnapshots.len() != 0 {
+		t.Errorf("Expected the snapshot to be dropped after the last page")
+	}
+
+	// A continue cannot outlive the snapshot of its list
+	s.listSnapshots = newListSnapshots(0)
+	first, err = s.List(context.Background(), &api.ListRequest{Version: "v1", Resource: "pod", Limit: 1})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	waitForCondition(t, func() bool { return s.listSnapshots.len() == 0 })
+	_, err = s.List(context.Background(), &api.ListRequest{Version: "v1", Resource: "pod", Limit: 1, Continue: first.Continue})
+	if status.Code(err) != codes.Aborted {
+		t.Errorf("Expected Aborted, got %v", err)
+	}
+}
+
+func TestListCachedThenWatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := newTestWatchServer(ctrl, newTestPod("default", "nginx"))
+	events, cancel, done := startTestWatch(ctrl, s, &api.WatchRequest{Version: "v1", Resource: "pod", Namespace: "default"})
+	defer func() {
+		cancel()
+		<-done
+	}()
+	waitForEventType(t, events, api.EventType_RESOLVED)
+	waitForEventType(t, events, api.EventType_ADD)
+	waitForEventType(t, events, api.EventType_SYNCED)
+
+	list, err := s.List(context.Background(), &api.ListRequest{Version: "v1", Resource: "pod", Namespace: "default"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !list.Cached || list.ResourceVersion != "1" {
+		t.Fatalf("Expected a cached list at resourceVersion 1, got %v", list)
+	}
+
+	// A change between the list and the watch is not lost
+	updated := newTestPod("default", "nginx")
+	updated.SetResourceVersion("2")
+	if _, err := s.dynamicClient.Resource(podsGVR).Namespace("default").Update(context.Background(), updated, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("Failed to update pod: %v", err)
+	}
+	resumed, cancelResumed, resumedDone := startTestWatch(ctrl, s, &api.WatchRequest{Version: "v1", Resource: "pod", Namespace: "default", ResourceVersion: list.ResourceVersion})
+	defer func() {
+		cancelResumed()
+		<-resumedDone
+	}()
+	waitForEventType(t, resumed, api.EventType_RESOLVED)
+	// The update is either replayed before SYNCED or follows it live
+	event := waitForEvent(t, resumed)
+	if event.Type == api.EventType_SYNCED {
+		event = waitForEvent(t, resumed)
+	}
+	if event.Type != api.EventType_UPDATE || event.ResourceVersion != "2" {
+		t.Errorf("Expected the update after the list, got %s at %s", event.Type, event.ResourceVersion)
+	}
+}
+
+func TestListCachedVersionBehindCache(t *testing.T) {
+	client := newFakeDynamicClient(newTestPod("default", "nginx"))
+	pods := client.Resource(podsGVR).Namespace("default")
+
+	// The history is fed by hand, so it can lag behind the cache the way it
+	// does while the informer has not yet called its handlers.
+	si := &sharedInformer{history: newEventHistory(historySize)}
+	si.informer = cache.NewSharedIndexInformer(
+		&cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				return pods.List(context.Background(), options)
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				return pods.Watch(context.Background(), options)
+			},
+		},
+		&unstructured.Unstructured{},
+		0,
+		cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc},
+	)
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	go si.informer.Run(stopCh)
+	waitForCondition(t, si.informer.HasSynced)
+	si.history.handler().OnAdd(newTestPod("default", "nginx"), true)
+
+	updated := newTestPod("default", "nginx")
+	updated.SetResourceVersion("2")
+	if _, err := pods.Update(context.Background(), updated, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("Failed to update pod: %v", err)
+	}
+	waitForCondition(t, func() bool { return si.informer.LastSyncResourceVersion() == "2" })
+
+	snapshot, err := snapshotCache(si, informerSpec{gvr: podsGVR, namespace: "default"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if snapshot.resourceVersion != "1" {
+		t.Errorf("Expected the resourceVersion the history has seen, got %s", snapshot.resourceVersion)
+	}
+}
+
+func TestListInvalidRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := newTestWatchServer(ctrl)
+	s.resolver = resolverFunc(func(gvr schema.GroupVersionResource) ([]schema.GroupVersionResource, error) {
+		if gvr.Resource == "all" {
+			return []schema.GroupVersionResource{podsGVR, deploymentsGVR}, nil
+		}
+		return []schema.GroupVersionResource{podsGVR}, nil
+	})
+	snapshot := &listSnapshot{key: informerSpec{gvr: podsGVR}.key(), objs: []interface{}{newTestPod("default", "nginx")}}
+	s.listSnapshots.add(snapshot)
+
+	tests := []struct {
+		name  string
+		req   *api.ListRequest
+		field string
+	}{
+		{name: "Negative limit", req: &api.ListRequest{Resource: "pods", Limit: -1}, field: "limit"},
+		{name: "Invalid selector", req: &api.ListRequest{Resource: "pods", LabelSelector: "app in (web"}, field: "label_selector"},
+		{name: "Category", req: &api.ListRequest{Resource: "all"}, field: "resource"},
+		{name: "Foreign continue", req: &api.ListRequest{Resource: "pods", Continue: "eyJ2IjoibWV0YS5rOHMuaW8vdjEifQ"}, field: "continue"},
+		{name: "Continue of another list", req: &api.ListRequest{Resource: "pods", Namespace: "other", Continue: encodeContinue(snapshot.id, 0)}, field: "continue"},
+		{name: "Continue past the list", req: &api.ListRequest{Resource: "pods", Continue: encodeContinue(snapshot.id, 2)}, field: "continue"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := s.List(context.Background(), tc.req)
+			st := status.Convert(err)
+			if st.Code() != codes.InvalidArgument {
+				t.Fatalf("Expected InvalidArgument, got %v", err)
+			}
+			assertStatusDetails(t, st, tc.field, "")
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/cmwylie19/watch-informer/api"
+
+	jsonpatch "gopkg.in/evanphx/json-patch.v4"
+)
+
+// jsonPatchOperation is a single RFC 6902 operation.
+type jsonPatchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// addUpdateDetails adds the previous object and the requested patch to an
+// UPDATE response. The response object must already be populated.
+func addUpdateDetails(resp *api.WatchResponse, oldObj interface{}, req *api.WatchRequest) error {
+	if !req.IncludeOldObject && req.PatchType == api.PatchType_NO_PATCH {
+		return nil
+	}
+
+	oldData, err := json.Marshal(oldObj)
+	if err != nil {
+		return fmt.Errorf("failed to convert old object to JSON: %w", err)
+	}
+	if req.IncludeOldObject {
+		resp.OldObject = oldData
+	}
+
+	switch req.PatchType {
+	case api.PatchType_JSON_PATCH:
+		resp.Patch, err = createJSONPatch(oldData, resp.Object)
+	case api.PatchType_MERGE_PATCH:
+		resp.Patch, err = jsonpatch.CreateMergePatch(oldData, resp.Object)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", req.PatchType, err)
+	}
+	return nil
+}
+
+// createJSONPatch returns the RFC 6902 patch turning original into modified.
+// Arrays that change length are replaced as a whole.
+func createJSONPatch(original, modified []byte) ([]byte, error) {
+	var from, to interface{}
+	if err := json.Unmarshal(original, &from); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(modified, &to); err != nil {
+		return nil, err
+	}
+
+	ops, err := diffJSON("", from, to, []jsonPatchOperation{})
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(ops)
+}
+
+func diffJSON(path string, from, to interface{}, ops []jsonPatchOperation) ([]jsonPatchOperation, error) {
+	if reflect.DeepEqual(from, to) {
+		return ops, nil
+	}
+
+	switch fromValue := from.(type) {
+	case map[string]interface{}:
+		toValue, ok := to.(map[string]interface{})
+		if !ok {
+			break
+		}
+		var err error
+		for _, key := range sortedKeys(fromValue) {
+			childPath := path + "/" + escapeJSONPointer(key)
+			if _, exists := toValue[key]; !exists {
+				ops = append(ops, jsonPatchOperation{Op: "remove", Path: childPath})
+				continue
+			}
+			if ops, err = diffJSON(childPath, fromValue[key], toValue[key], ops); err != nil {
+				return nil, err
+			}
+		}
+		for _, key := range sortedKeys(toValue) {
+			if _, exists := fromValue[key]; exists {
+				continue
+			}
+			if ops, err = appendValueOperation(ops, "add", path+"/"+escapeJSONPointer(key), toValue[key]); err != nil {
+				return nil, err
+			}
+		}
+		return ops, nil
+	case []interface{}:
+		toValue, ok := to.([]interface{})
+		if !ok || len(fromValue) != len(toValue) {
+			break
+		}
+		var err error
+		for i := range fromValue {
+			if ops, err = diffJSON(path+"/"+strconv.Itoa(i), fromValue[i], toValue[i], ops); err != nil {
+				return nil, err
+			}
+		}
+		return ops, nil
+	}
+	return appendValueOperation(ops, "replace", path, to)
+}
+
+func appendValueOperation(ops []jsonPatchOperation, op, path string, value interface{}) ([]jsonPatchOperation, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	return append(ops, jsonPatchOperation{Op: op, Path: path, Value: data}), nil
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func escapeJSONPointer(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
@@ -0,0 +1,144 @@
+package server
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/cmwylie19/watch-informer/api"
+
+	jsonpatch "gopkg.in/evanphx/json-patch.v4"
+)
+
+func TestCreateJSONPatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		original string
+		modified string
+		expected string
+	}{
+		{
+			name:     "No changes",
+			original: `{"a":1}`,
+			modified: `{"a":1}`,
+			expected: `[]`,
+		},
+		{
+			name:     "Replace, add and remove",
+			original: `{"a":1,"b":{"c":"x"},"d":true}`,
+			modified: `{"a":2,"b":{"c":"x","e":null}}`,
+			expected: `[{"op":"replace","path":"/a","value":2},{"op":"add","path":"/b/e","value":null},{"op":"remove","path":"/d"}]`,
+		},
+		{
+			name:     "Array element changed",
+			original: `{"l":[1,2]}`,
+			modified: `{"l":[1,3]}`,
+			expected: `[{"op":"replace","path":"/l/1","value":3}]`,
+		},
+		{
+			name:     "Array length changed",
+			original: `{"l":[1]}`,
+			modified: `{"l":[1,2]}`,
+			expected: `[{"op":"replace","path":"/l","value":[1,2]}]`,
+		},
+		{
+			name:     "Escaped keys",
+			original: `{"metadata":{"labels":{"app.io/name":"a"}}}`,
+			modified: `{"metadata":{"labels":{"app.io/name":"b","x~y":"c"}}}`,
+			expected: `[{"op":"replace","path":"/metadata/labels/app.io~1name","value":"b"},{"op":"add","path":"/metadata/labels/x~0y","value":"c"}]`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := createJSONPatch([]byte(tc.original), []byte(tc.modified))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if string(actual) != tc.expected {
+				t.Errorf("expected: %s, got: %s", tc.expected, actual)
+			}
+
+			patch, err := jsonpatch.DecodePatch(actual)
+			if err != nil {
+				t.Fatalf("Failed to decode patch: %v", err)
+			}
+			applied, err := patch.Apply([]byte(tc.original))
+			if err != nil {
+				t.Fatalf("Failed to apply patch: %v", err)
+			}
+			assertEqualJSON(t, tc.modified, applied)
+		})
+	}
+}
+
+func TestAddUpdateDetails(t *testing.T) {
+	oldPod := newTestPod("default", "nginx")
+	newPod := oldPod.DeepCopy()
+	newPod.SetLabels(map[string]string{"app": "nginx"})
+
+	tests := []struct {
+		name          string
+		req           *api.WatchRequest
+		wantOldObject bool
+		expectedPatch string
+	}{
+		{
+			name: "No options",
+			req:  &api.WatchRequest{},
+		},
+		{
+			name:          "Old object only",
+			req:           &api.WatchRequest{IncludeOldObject: true},
+			wantOldObject: true,
+		},
+		{
+			name:          "JSON patch",
+			req:           &api.WatchRequest{PatchType: api.PatchType_JSON_PATCH},
+			expectedPatch: `[{"op":"add","path":"/metadata/labels","value":{"app":"nginx"}}]`,
+		},
+		{
+			name:          "Merge patch with old object",
+			req:           &api.WatchRequest{IncludeOldObject: true, PatchType: api.PatchType_MERGE_PATCH},
+			wantOldObject: true,
+			expectedPatch: `{"metadata":{"labels":{"app":"nginx"}}}`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			resp := newWatchResponse(api.EventType_UPDATE, newPod)
+			if err := addUpdateDetails(resp, oldPod, tc.req); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if (resp.OldObject != nil) != tc.wantOldObject {
+				t.Errorf("expected old object: %v, got: %s", tc.wantOldObject, resp.OldObject)
+			}
+			if tc.wantOldObject {
+				oldData, _ := json.Marshal(oldPod)
+				assertEqualJSON(t, string(oldData), resp.OldObject)
+			}
+			if tc.expectedPatch == "" && resp.Patch != nil {
+				t.Errorf("expected no patch, got: %s", resp.Patch)
+			}
+			if tc.expectedPatch != "" {
+				assertEqualJSON(t, tc.expectedPatch, resp.Patch)
+			}
+		})
+	}
+}
+
+func assertEqualJSON(t *testing.T, expected string, actual []byte) {
+	t.Helper()
+	var expectedValue, actualValue interface{}
+	if err := json.Unmarshal([]byte(expected), &expectedValue); err != nil {
+		t.Fatalf("Invalid expected JSON: %v", err)
+	}
+	if err := json.Unmarshal(actual, &actualValue); err != nil {
+		t.Fatalf("Invalid actual JSON: %v", err)
+	}
+	if !reflect.DeepEqual(expectedValue, actualValue) {
+		t.Errorf("expected: %s, got: %s", expected, actual)
+	}
+}
@@ -0,0 +1,130 @@
+package server
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cmwylie19/watch-informer/api"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// projection trims the objects sent for a subscription. Objects in the
+// informer cache are shared, so it copies every map it changes.
+type projection struct {
+	fields [][]string
+	strip  bool
+}
+
+// newProjection returns the projection req asks for, or nil to send whole
+// objects.
+func newProjection(req *api.WatchRequest) *projection {
+	if len(req.Fields) == 0 && !req.StripManagedFields {
+		return nil
+	}
+	p := &projection{strip: req.StripManagedFields}
+	for _, field := range req.Fields {
+		p.fields = append(p.fields, strings.Split(field, "."))
+	}
+	return p
+}
+
+// validatePaths checks that every path of the request field is a
+// dot-separated path.
+func validatePaths(field string, paths []string) error {
+	for i, path := range paths {
+		for _, name := range strings.Split(path, ".") {
+			if name == "" {
+				return invalidArgument(fmt.Sprintf("%s[%d]", field, i), "must be a dot-separated path such as metadata.labels")
+			}
+		}
+	}
+	return nil
+}
+
+// apply returns the trimmed content of obj. Anything but a Kubernetes
+// object is returned as is.
+func (p *projection) apply(obj interface{}) interface{} {
+	if p == nil {
+		return obj
+	}
+	content, ok := objectContent(obj)
+	if !ok {
+		return obj
+	}
+	if len(p.fields) > 0 {
+		content = selectFields(content, p.fields)
+	}
+	if p.strip {
+		content = stripManagedFields(content)
+	}
+	return content
+}
+
+// selectFields copies the values at paths into a new object.
+func selectFields(content map[string]interface{}, paths [][]string) map[string]interface{} {
+	selected := map[string]interface{}{}
+	for _, path := range paths {
+		value, ok := content, true
+		var found interface{}
+		for i, name := range path {
+			found, ok = value[name]
+			if !ok {
+				break
+			}
+			if i < len(path)-1 {
+				if value, ok = found.(map[string]interface{}); !ok {
+					break
+				}
+			}
+		}
+		if !ok {
+			continue
+		}
+
+		parent := selected
+		for _, name := range path[:len(path)-1] {
+			child, ok := parent[name].(map[string]interface{})
+			if !ok {
+				child = map[string]interface{}{}
+				parent[name] = child
+			}
+			parent = child
+		}
+		parent[path[len(path)-1]] = found
+	}
+	return selected
+}
+
+// stripManagedFields leaves out the bookkeeping that makes up most of a
+// typical object: managedFields and the annotation kubectl apply keeps.
+func stripManagedFields(content map[string]interface{}) map[string]interface{} {
+	metadata, ok := content["metadata"].(map[string]interface{})
+	if !ok {
+		return content
+	}
+	annotations, _ := metadata["annotations"].(map[string]interface{})
+	_, lastApplied := annotations[corev1.LastAppliedConfigAnnotation]
+	if _, managed := metadata["managedFields"]; !managed && !lastApplied {
+		return content
+	}
+
+	stripped := copyMap(metadata)
+	delete(stripped, "managedFields")
+	if lastApplied {
+		annotations = copyMap(annotations)
+		delete(annotations, corev1.LastAppliedConfigAnnotation)
+		stripped["annotations"] = annotations
+	}
+	content = copyMap(content)
+	content["metadata"] = stripped
+	return content
+}
+
+func copyMap(m map[string]interface{}) map[string]interface{} {
+	copied := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		copied[k] = v
+	}
+	return copied
+}
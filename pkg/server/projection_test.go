@@ -0,0 +1,121 @@
+package server
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/cmwylie19/watch-informer/api"
+)
+
+func newTestAppliedPod() *unstructured.Unstructured {
+	pod := newTestPodInPhase("nginx", "Running")
+	pod.SetLabels(map[string]string{"app": "nginx"})
+	pod.SetAnnotations(map[string]string{
+		corev1.LastAppliedConfigAnnotation: `{"apiVersion":"v1","kind":"Pod"}`,
+		"team":                             "payments",
+	})
+	pod.Object["metadata"].(map[string]interface{})["managedFields"] = []interface{}{
+		map[string]interface{}{"manager": "kubectl", "operation": "Apply"},
+	}
+	return pod
+}
+
+func TestProjection(t *testing.T) {
+	tests := []struct {
+		name     string
+		req      *api.WatchRequest
+		expected string
+	}{
+		{
+			name:     "Fields",
+			req:      &api.WatchRequest{Fields: []string{"metadata.name", "metadata.labels", "status.phase"}},
+			expected: `{"metadata":{"labels":{"app":"nginx"},"name":"nginx"},"status":{"phase":"Running"}}`,
+		},
+		{
+			name:     "Missing field",
+			req:      &api.WatchRequest{Fields: []string{"metadata.name", "spec.nodeName", "status.phase.reason"}},
+			expected: `{"metadata":{"name":"nginx"}}`,
+		},
+		{
+			name:     "Strip managed fields",
+			req:      &api.WatchRequest{Fields: []string{"metadata"}, StripManagedFields: true},
+			expected: `{"metadata":{"annotations":{"team":"payments"},"labels":{"app":"nginx"},"name":"nginx","namespace":"default","resourceVersion":"1"}}`,
+		},
+		{
+			name:     "Strip only",
+			req:      &api.WatchRequest{StripManagedFields: true},
+			expected: `{"apiVersion":"v1","kind":"Pod","metadata":{"annotations":{"team":"payments"},"labels":{"app":"nginx"},"name":"nginx","namespace":"default","resourceVersion":"1"},"status":{"phase":"Running"}}`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			pod := newTestAppliedPod()
+			original := pod.DeepCopy()
+
+			data, err := json.Marshal(newProjection(tc.req).apply(pod))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if string(data) != tc.expected {
+				t.Errorf("expected: %s, got: %s", tc.expected, data)
+			}
+			if !reflect.DeepEqual(pod, original) {
+				t.Errorf("Expected the object to be left unchanged, got %v", pod.Object)
+			}
+		})
+	}
+
+	if p := newProjection(&api.WatchRequest{}); p != nil {
+		t.Errorf("Expected no projection without fields, got %v", p)
+	}
+}
+
+func TestProjectedWatchResponse(t *testing.T) {
+	pod := newTestAppliedPod()
+	p := newProjection(&api.WatchRequest{Fields: []string{"status"}})
+	resp := newProjectedWatchResponse(api.EventType_DELETE, cache.DeletedFinalStateUnknown{Key: "default/nginx", Obj: pod}, p, schema.GroupVersionKind{})
+
+	if string(resp.Object) != `{"status":{"phase":"Running"}}` {
+		t.Errorf("Expected only the status, got %s", resp.Object)
+	}
+	if resp.Kind != "Pod" || resp.Namespace != "default" || resp.Name != "nginx" || resp.ResourceVersion != "1" {
+		t.Errorf("Expected the metadata of the whole object, got %v", resp)
+	}
+	if !resp.FinalStateUnknown {
+		t.Error("Expected the tombstone to be unwrapped")
+	}
+}
+
+func TestWatchFields(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := newTestWatchServer(ctrl, newTestAppliedPod())
+	events, cancel, done := startTestWatch(ctrl, s, &api.WatchRequest{
+		Version:            "v1",
+		Resource:           "pod",
+		Namespace:          "default",
+		Fields:             []string{"metadata.labels", "metadata.annotations"},
+		StripManagedFields: true,
+	})
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	waitForEventType(t, events, api.EventType_RESOLVED)
+	event := waitForEventType(t, events, api.EventType_ADD)
+	if expected := `{"metadata":{"annotations":{"team":"payments"},"labels":{"app":"nginx"}}}`; string(event.Object) != expected {
+		t.Errorf("expected: %s, got: %s", expected, event.Object)
+	}
+	if event.Name != "nginx" {
+		t.Errorf("Expected the name of the pod, got %s", event.Name)
+	}
+}
@@ -0,0 +1,375 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/cmwylie19/watch-informer/api"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/cache"
+)
+
+const (
+	defaultBufferSize   = 100
+	maxBufferSize       = 10000
+	defaultBlockTimeout = 5 * time.Second
+	maxBlockTimeout     = 30 * time.Second
+)
+
+var (
+	errQueueClosed   = errors.New("event queue is closed")
+	errQueueOverflow = status.Error(codes.ResourceExhausted, "event queue is full, disconnecting slow client")
+)
+
+// queuedEvent is an informer event waiting to be sent on a session. Control
+// events such as SYNCED and OVERFLOW carry a prebuilt response instead.
+type queuedEvent struct {
+	eventType     api.EventType
+	obj           interface{}
+	oldObj        interface{}
+	isInitialList bool
+	// kind is what the object is when it does not say, as with metadata-only
+	// watches.
+	kind     schema.GroupVersionKind
+	response *api.WatchResponse
+	// subscription is the target the event belongs to, nil for events that
+	// concern the whole stream.
+	subscription *subscription
+}
+
+// done is closed once the subscription of e is stopped, so a handler
+// blocked on a full queue does not hold up unsubscribing.
+func (e *queuedEvent) done() <-chan struct{} {
+	if e.subscription == nil || e.subscription.ctx == nil {
+		return nil
+	}
+	return e.subscription.ctx.Done()
+}
+
+func (e *queuedEvent) isOverflow() bool {
+	return e.response != nil && e.response.Type == api.EventType_OVERFLOW
+}
+
+// eventKey identifies an object within a subscription. Targets can overlap,
+// so the same object seen by two subscriptions is pending twice.
+type eventKey struct {
+	subscription *subscription
+	object       string
+}
+
+// key identifies the object an event is about.
+func (e *queuedEvent) key() eventKey {
+	key, _ := cache.DeletionHandlingMetaNamespaceKeyFunc(e.obj)
+	return eventKey{subscription: e.subscription, object: key}
+}
+
+// eventQueue is a bounded FIFO of events waiting to be sent on a session.
+// When it is full the backpressure policy decides what happens to new
+// events, and any loss is recorded as an OVERFLOW event at that point in
+// the stream. Control events are never dropped and do not count towards
+// the capacity.
+//
+// With the COALESCE policy the queue is keyed instead, much like client-go's
+// DeltaFIFO: pending events for the same object collapse into its latest
+// state while ADD and DELETE ordering is kept, so at most two events per
+// object are ever pending and the capacity does not apply.
+type eventQueue struct {
+	mu       sync.Mutex
+	events   []*queuedEvent
+	pending  map[eventKey][]*queuedEvent
+	size     int
+	capacity int
+	policy   api.BackpressurePolicy
+	timeout  time.Duration
+	closed   bool
+	err      error
+	// added is signalled when events are queued, removed is closed and
+	// replaced whenever events are taken off the queue.
+	added   chan struct{}
+	removed chan struct{}
+}
+
+func newEventQueue(capacity int, policy api.BackpressurePolicy, timeout time.Duration) *eventQueue {
+	return &eventQueue{
+		capacity: capacity,
+		pending:  make(map[eventKey][]*queuedEvent),
+		policy:   policy,
+		timeout:  timeout,
+		added:    make(chan struct{}, 1),
+		removed:  make(chan struct{}),
+	}
+}
+
+// push queues an event, applying the backpressure policy when the queue is
+// full. It reports whether the event was queued.
+func (q *eventQueue) push(event *queuedEvent) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.closed || q.err != nil {
+		return false
+	}
+	if event.response == nil && q.policy == api.BackpressurePolicy_COALESCE {
+		q.coalesce(event)
+		return true
+	}
+	if event.response != nil || q.size < q.capacity {
+		q.append(event)
+		return true
+	}
+
+	switch q.policy {
+	case api.BackpressurePolicy_BLOCK:
+		done := event.done()
+		if q.waitForSpace(done) {
+			q.append(event)
+			return true
+		}
+		if q.closed || q.err != nil {
+			return false
+		}
+		select {
+		case <-done:
+			// The subscription is gone, so nothing was lost
+			return false
+		default:
+		}
+	case api.BackpressurePolicy_DROP_OLDEST:
+		q.dropOldest()
+		q.append(event)
+		return true
+	case api.BackpressurePolicy_DISCONNECT:
+		q.err = errQueueOverflow
+		q.signal()
+		return false
+	}
+	q.dropNewest()
+	return false
+}
+
+// next blocks until an event is available and removes it from the queue.
+func (q *eventQueue) next(ctx context.Context) (*queuedEvent, error) {
+	for {
+		q.mu.Lock()
+		if q.err != nil {
+			q.mu.Unlock()
+			return nil, q.err
+		}
+		if q.closed {
+			q.mu.Unlock()
+			return nil, errQueueClosed
+		}
+		if len(q.events) > 0 {
+			event := q.events[0]
+			q.events[0] = nil
+			q.events = q.events[1:]
+			if event.response == nil {
+				q.size--
+				q.unindex(event)
+			}
+			close(q.removed)
+			q.removed = make(chan struct{})
+			q.mu.Unlock()
+			return event, nil
+		}
+		q.mu.Unlock()
+
+		select {
+		case <-q.added:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// close empties the queue, wakes anything waiting on it and returns the
+// events that were still pending.
+func (q *eventQueue) close() []*queuedEvent {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.closed {
+		return nil
+	}
+	pending := q.events
+	q.closed = true
+	q.events = nil
+	q.pending = make(map[eventKey][]*queuedEvent)
+	q.size = 0
+	close(q.removed)
+	q.signal()
+	return pending
+}
+
+// len returns the number of queued events, including control events.
+func (q *eventQueue) len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.events)
+}
+
+func (q *eventQueue) append(event *queuedEvent) {
+	q.events = append(q.events, event)
+	if event.response == nil {
+		q.size++
+	}
+	q.signal()
+}
+
+func (q *eventQueue) signal() {
+	select {
+	case q.added <- struct{}{}:
+	default:
+	}
+}
+
+// waitForSpace releases the lock until an event is removed, the timeout
+// passes or done is closed, and reports whether there is room for another
+// event.
+func (q *eventQueue) waitForSpace(done <-chan struct{}) bool {
+	timer := time.NewTimer(q.timeout)
+	defer timer.Stop()
+
+	for q.size >= q.capacity {
+		if q.closed || q.err != nil {
+			return false
+		}
+		removed := q.removed
+		q.mu.Unlock()
+		select {
+		case <-removed:
+			q.mu.Lock()
+		case <-timer.C:
+			q.mu.Lock()
+			return !q.closed && q.err == nil && q.size < q.capacity
+		case <-done:
+			q.mu.Lock()
+			return false
+		}
+	}
+	return !q.closed && q.err == nil
+}
+
+// dropNewest records that an event arriving after everything queued was lost.
+func (q *eventQueue) dropNewest() {
+	if last := len(q.events) - 1; last >= 0 && q.events[last].isOverflow() {
+		q.events[last].response.Dropped++
+		return
+	}
+	q.append(newOverflowEvent())
+}
+
+// dropOldest removes the oldest object event and records the loss in its place.
+func (q *eventQueue) dropOldest() {
+	for i, event := range q.events {
+		if event.response != nil {
+			continue
+		}
+		q.size--
+		if i > 0 && q.events[i-1].isOverflow() {
+			q.events[i-1].response.Dropped++
+			q.events = append(q.events[:i], q.events[i+1:]...)
+			return
+		}
+		q.events[i] = newOverflowEvent()
+		return
+	}
+}
+
+// coalesce merges event with the events pending for the same object.
+func (q *eventQueue) coalesce(event *queuedEvent) {
+	key := event.key()
+	pending := q.pending[key]
+	if len(pending) == 0 {
+		q.append(event)
+		q.pending[key] = []*queuedEvent{event}
+		return
+	}
+
+	last := pending[len(pending)-1]
+	switch {
+	case event.eventType == api.EventType_RESYNC:
+		// The pending events already carry the latest state
+	case event.eventType == api.EventType_UPDATE && last.eventType != api.EventType_DELETE:
+		// ADD+UPDATE stays an ADD and UPDATE+UPDATE keeps the first old object
+		last.obj = event.obj
+	case event.eventType == api.EventType_DELETE && last.eventType == api.EventType_UPDATE:
+		last.eventType = api.EventType_DELETE
+		last.obj = event.obj
+		last.oldObj = nil
+	case event.eventType == api.EventType_DELETE && last.eventType == api.EventType_ADD:
+		// The client never saw the object, so the ADD and DELETE cancel out
+		q.remove(last)
+		if len(pending) == 1 {
+			delete(q.pending, key)
+		} else {
+			q.pending[key] = pending[:len(pending)-1]
+		}
+	default:
+		q.append(event)
+		q.pending[key] = append(pending, event)
+	}
+}
+
+// discard drops every event queued for sub.
+func (q *eventQueue) discard(sub *subscription) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	kept := q.events[:0]
+	for _, event := range q.events {
+		if event.subscription != sub {
+			kept = append(kept, event)
+			continue
+		}
+		if event.response == nil {
+			q.size--
+			delete(q.pending, event.key())
+		}
+	}
+	if len(kept) == len(q.events) {
+		return
+	}
+	clear(q.events[len(kept):])
+	q.events = kept
+	close(q.removed)
+	q.removed = make(chan struct{})
+}
+
+// remove takes a pending object event out of the queue.
+func (q *eventQueue) remove(event *queuedEvent) {
+	for i := range q.events {
+		if q.events[i] == event {
+			q.events = append(q.events[:i], q.events[i+1:]...)
+			q.size--
+			return
+		}
+	}
+}
+
+// unindex forgets an event that is no longer pending.
+func (q *eventQueue) unindex(event *queuedEvent) {
+	key := event.key()
+	pending := q.pending[key]
+	if len(pending) == 0 || pending[0] != event {
+		return
+	}
+	if len(pending) == 1 {
+		delete(q.pending, key)
+		return
+	}
+	q.pending[key] = pending[1:]
+}
+
+func newOverflowEvent() *queuedEvent {
+	return &queuedEvent{response: &api.WatchResponse{
+		EventType: api.EventType_OVERFLOW.String(),
+		Type:      api.EventType_OVERFLOW,
+		Dropped:   1,
+	}}
+}
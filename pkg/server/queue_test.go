@@ -0,0 +1,286 @@
+package server
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/cmwylie19/watch-informer/api"
+)
+
+func testEvent(eventType api.EventType, name, resourceVersion string) *queuedEvent {
+	pod := newTestPod("default", name)
+	pod.SetResourceVersion(resourceVersion)
+	return &queuedEvent{eventType: eventType, obj: pod}
+}
+
+// drainQueue returns the queued events as "TYPE/name@rv", or "OVERFLOW/n".
+func drainQueue(t *testing.T, q *eventQueue) []string {
+	t.Helper()
+	var events []string
+	for q.len() > 0 {
+		event, err := q.next(context.Background())
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if event.response != nil {
+			events = append(events, event.response.EventType+"/"+strconv.FormatUint(event.response.Dropped, 10))
+			continue
+		}
+		pod := event.obj.(interface {
+			GetName() string
+			GetResourceVersion() string
+		})
+		events = append(events, event.eventType.String()+"/"+pod.GetName()+"@"+pod.GetResourceVersion())
+	}
+	return events
+}
+
+func assertEvents(t *testing.T, expected, actual []string) {
+	t.Helper()
+	if len(expected) != len(actual) {
+		t.Fatalf("expected: %v, got: %v", expected, actual)
+	}
+	for i := range expected {
+		if expected[i] != actual[i] {
+			t.Fatalf("expected: %v, got: %v", expected, actual)
+		}
+	}
+}
+
+func TestEventQueuePolicies(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   api.BackpressurePolicy
+		events   []*queuedEvent
+		expected []string
+	}{
+		{
+			name:   "Drop newest",
+			policy: api.BackpressurePolicy_DROP_NEWEST,
+			events: []*queuedEvent{
+				testEvent(api.EventType_ADD, "a", "1"),
+				testEvent(api.EventType_ADD, "b", "2"),
+				testEvent(api.EventType_ADD, "c", "3"),
+				testEvent(api.EventType_DELETE, "a", "4"),
+			},
+			expected: []string{"ADD/a@1", "ADD/b@2", "OVERFLOW/2"},
+		},
+		{
+			name:   "Drop oldest",
+			policy: api.BackpressurePolicy_DROP_OLDEST,
+			events: []*queuedEvent{
+				testEvent(api.EventType_ADD, "a", "1"),
+				testEvent(api.EventType_ADD, "b", "2"),
+				testEvent(api.EventType_ADD, "c", "3"),
+				testEvent(api.EventType_DELETE, "a", "4"),
+			},
+			expected: []string{"OVERFLOW/2", "ADD/c@3", "DELETE/a@4"},
+		},
+		{
+			name:   "Coalesce updates for the same object",
+			policy: api.BackpressurePolicy_COALESCE,
+			events: []*queuedEvent{
+				testEvent(api.EventType_ADD, "a", "1"),
+				testEvent(api.EventType_UPDATE, "b", "2"),
+				testEvent(api.EventType_UPDATE, "a", "3"),
+				testEvent(api.EventType_UPDATE, "b", "4"),
+				testEvent(api.EventType_UPDATE, "c", "5"),
+			},
+			expected: []string{"ADD/a@3", "UPDATE/b@4", "UPDATE/c@5"},
+		},
+		{
+			name:   "Coalesce keeps ADD and DELETE ordering",
+			policy: api.BackpressurePolicy_COALESCE,
+			events: []*queuedEvent{
+				testEvent(api.EventType_UPDATE, "a", "1"),
+				testEvent(api.EventType_DELETE, "a", "2"),
+				testEvent(api.EventType_ADD, "a", "3"),
+				testEvent(api.EventType_UPDATE, "a", "4"),
+				testEvent(api.EventType_ADD, "b", "5"),
+				testEvent(api.EventType_DELETE, "b", "6"),
+			},
+			expected: []string{"DELETE/a@2", "ADD/a@4"},
+		},
+		{
+			name:   "Coalesce drops resyncs of pending objects",
+			policy: api.BackpressurePolicy_COALESCE,
+			events: []*queuedEvent{
+				testEvent(api.EventType_ADD, "a", "1"),
+				testEvent(api.EventType_RESYNC, "a", "1"),
+				testEvent(api.EventType_RESYNC, "b", "2"),
+			},
+			expected: []string{"ADD/a@1", "RESYNC/b@2"},
+		},
+		{
+			name:   "Control events are never dropped",
+			policy: api.BackpressurePolicy_DROP_NEWEST,
+			events: []*queuedEvent{
+				testEvent(api.EventType_ADD, "a", "1"),
+				testEvent(api.EventType_ADD, "b", "2"),
+				{response: newSyncedResponse("2")},
+			},
+			expected: []string{"ADD/a@1", "ADD/b@2", "SYNCED/0"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			q := newEventQueue(2, tc.policy, time.Millisecond)
+			for _, event := range tc.events {
+				q.push(event)
+			}
+			assertEvents(t, tc.expected, drainQueue(t, q))
+		})
+	}
+}
+
+func TestEventQueueBlock(t *testing.T) {
+	q := newEventQueue(1, api.BackpressurePolicy_BLOCK, 5*time.Second)
+	q.push(testEvent(api.EventType_ADD, "a", "1"))
+
+	pushed := make(chan bool)
+	go func() {
+		pushed <- q.push(testEvent(api.EventType_ADD, "b", "2"))
+	}()
+
+	select {
+	case <-pushed:
+		t.Fatalf("Expected push to block while the queue is full")
+	case <-time.After(50 * time.Millisecond):
+	}
+	if _, err := q.next(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !<-pushed {
+		t.Errorf("Expected blocked push to succeed once there is room")
+	}
+	assertEvents(t, []string{"ADD/b@2"}, drainQueue(t, q))
+}
+
+func TestEventQueueBlockTimeout(t *testing.T) {
+	q := newEventQueue(1, api.BackpressurePolicy_BLOCK, 10*time.Millisecond)
+	q.push(testEvent(api.EventType_ADD, "a", "1"))
+
+	if q.push(testEvent(api.EventType_ADD, "b", "2")) {
+		t.Errorf("Expected push to fail after the block timeout")
+	}
+	assertEvents(t, []string{"ADD/a@1", "OVERFLOW/1"}, drainQueue(t, q))
+}
+
+func TestEventQueueDisconnect(t *testing.T) {
+	q := newEventQueue(1, api.BackpressurePolicy_DISCONNECT, time.Millisecond)
+	q.push(testEvent(api.EventType_ADD, "a", "1"))
+
+	if q.push(testEvent(api.EventType_ADD, "b", "2")) {
+		t.Errorf("Expected push to fail on overflow")
+	}
+	if _, err := q.next(context.Background()); err != errQueueOverflow {
+		t.Errorf("Expected overflow error, got %v", err)
+	}
+}
+
+func TestEventQueueNextCanceled(t *testing.T) {
+	q := newEventQueue(1, api.BackpressurePolicy_DROP_NEWEST, time.Millisecond)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := q.next(ctx); err != context.Canceled {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+func TestEventQueueCoalesceAfterSend(t *testing.T) {
+	q := newEventQueue(1, api.BackpressurePolicy_COALESCE, time.Millisecond)
+	q.push(testEvent(api.EventType_ADD, "a", "1"))
+	q.push(testEvent(api.EventType_UPDATE, "a", "2"))
+	assertEvents(t, []string{"ADD/a@2"}, drainQueue(t, q))
+
+	// Once sent, new events for the object are queued separately again.
+	q.push(testEvent(api.EventType_UPDATE, "a", "3"))
+	q.push(testEvent(api.EventType_UPDATE, "a", "4"))
+	q.push(testEvent(api.EventType_DELETE, "a", "5"))
+	assertEvents(t, []string{"DELETE/a@5"}, drainQueue(t, q))
+
+	if len(q.pending) != 0 {
+		t.Errorf("Expected no pending keys, got %d", len(q.pending))
+	}
+}
+
+func TestEventQueueCoalesceKeepsOldObject(t *testing.T) {
+	q := newEventQueue(1, api.BackpressurePolicy_COALESCE, time.Millisecond)
+	first := testEvent(api.EventType_UPDATE, "a", "2")
+	first.oldObj = newTestPodAt("a", "1")
+	q.push(first)
+	q.push(&queuedEvent{eventType: api.EventType_UPDATE, obj: newTestPodAt("a", "3"), oldObj: newTestPodAt("a", "2")})
+
+	event, err := q.next(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if objectResourceVersion(event.oldObj) != 1 || objectResourceVersion(event.obj) != 3 {
+		t.Errorf("Expected UPDATE from resourceVersion 1 to 3, got %d to %d", objectResourceVersion(event.oldObj), objectResourceVersion(event.obj))
+	}
+}
+
+func TestEventQueueCoalescePerSubscription(t *testing.T) {
+	q := newEventQueue(1, api.BackpressurePolicy_COALESCE, time.Millisecond)
+	first, second := &subscription{id: "a"}, &subscription{id: "b"}
+	for _, sub := range []*subscription{first, second} {
+		event := testEvent(api.EventType_UPDATE, "a", "2")
+		event.subscription = sub
+		q.push(event)
+	}
+	assertEvents(t, []string{"UPDATE/a@2", "UPDATE/a@2"}, drainQueue(t, q))
+}
+
+func TestEventQueueDiscard(t *testing.T) {
+	q := newEventQueue(2, api.BackpressurePolicy_COALESCE, time.Millisecond)
+	kept, discarded := &subscription{id: "a"}, &subscription{id: "b"}
+	for _, event := range []*queuedEvent{
+		{response: newResolvedResponse(nil), subscription: discarded},
+		{eventType: api.EventType_ADD, obj: newTestPodAt("a", "1"), subscription: discarded},
+		{eventType: api.EventType_ADD, obj: newTestPodAt("a", "1"), subscription: kept},
+	} {
+		q.push(event)
+	}
+
+	q.discard(discarded)
+	if len(q.pending) != 1 || q.size != 1 {
+		t.Errorf("Expected only the kept event to be pending, got %d keys and size %d", len(q.pending), q.size)
+	}
+	assertEvents(t, []string{"ADD/a@1"}, drainQueue(t, q))
+}
+
+func TestEventQueueBlockStopped(t *testing.T) {
+	q := newEventQueue(1, api.BackpressurePolicy_BLOCK, time.Minute)
+	ctx, cancel := context.WithCancel(context.Background())
+	sub := &subscription{id: "a", ctx: ctx, cancel: cancel}
+	first := testEvent(api.EventType_ADD, "a", "1")
+	first.subscription = sub
+	q.push(first)
+
+	pushed := make(chan bool)
+	go func() {
+		second := testEvent(api.EventType_ADD, "b", "2")
+		second.subscription = sub
+		pushed <- q.push(second)
+	}()
+
+	select {
+	case <-pushed:
+		t.Fatalf("Expected push to block while the queue is full")
+	case <-time.After(50 * time.Millisecond):
+	}
+	sub.stop()
+	select {
+	case ok := <-pushed:
+		if ok {
+			t.Errorf("Expected the push of a stopped subscription to fail")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Expected a blocked push to give up once its subscription is stopped")
+	}
+	assertEvents(t, []string{"ADD/a@1"}, drainQueue(t, q))
+}
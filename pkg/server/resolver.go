@@ -0,0 +1,167 @@
+package server
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
+)
+
+const (
+	// discoveryRefreshInterval is how long discovery information is cached.
+	discoveryRefreshInterval = 10 * time.Minute
+	// discoveryMissInterval limits how often a resource that cannot be found
+	// refreshes the cache, so typos do not hammer the API server.
+	discoveryMissInterval = 10 * time.Second
+)
+
+// resourceResolver resolves the resource named in a request to the full
+// group, version and plural resource names that the API server serves.
+type resourceResolver interface {
+	// resourcesFor returns the one resource gvr names, or every resource in
+	// the category it names.
+	resourcesFor(gvr schema.GroupVersionResource) ([]schema.GroupVersionResource, error)
+	// kindFor returns the kind of a resource resourcesFor returned.
+	kindFor(gvr schema.GroupVersionResource) (schema.GroupVersionKind, error)
+	// invalidate makes later lookups see the resources added or removed by
+	// the API change at resourceVersion, or by an unknown change when it is
+	// zero.
+	invalidate(resourceVersion uint64)
+}
+
+// resolverFunc adapts a function to a resourceResolver.
+type resolverFunc func(schema.GroupVersionResource) ([]schema.GroupVersionResource, error)
+
+func (f resolverFunc) resourcesFor(gvr schema.GroupVersionResource) ([]schema.GroupVersionResource, error) {
+	return f(gvr)
+}
+
+// kindFor fails, as a function only resolves resources.
+func (f resolverFunc) kindFor(gvr schema.GroupVersionResource) (schema.GroupVersionKind, error) {
+	return schema.GroupVersionKind{}, &meta.NoResourceMatchError{PartialResource: gvr}
+}
+
+func (f resolverFunc) invalidate(uint64) {}
+
+// discoveryResolver resolves plurals, singulars, kinds, short names and
+// categories with a RESTMapper over a memory-cached discovery client. An
+// empty group matches any group, preferring the core group, and an empty
+// version picks the preferred version.
+type discoveryResolver struct {
+	cache      *restmapper.DeferredDiscoveryRESTMapper
+	mapper     meta.RESTMapper
+	categories restmapper.CategoryExpander
+	now        func() time.Time
+
+	mu          sync.Mutex
+	lastRefresh time.Time
+	invalidated time.Time
+	// invalidatedVersion is the newest API change invalidated so far.
+	invalidatedVersion uint64
+}
+
+func newDiscoveryResolver(client discovery.DiscoveryInterface) *discoveryResolver {
+	cached := memory.NewMemCacheClient(client)
+	deferred := restmapper.NewDeferredDiscoveryRESTMapper(cached)
+	return &discoveryResolver{
+		cache:      deferred,
+		mapper:     restmapper.NewShortcutExpander(deferred, cached, nil),
+		categories: restmapper.NewDiscoveryCategoryExpander(cached),
+		now:        time.Now,
+	}
+}
+
+func newDiscoveryResolverForConfig(restConfig *rest.Config) (*discoveryResolver, error) {
+	client, err := discovery.NewDiscoveryClientForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create discovery client: %w", err)
+	}
+	return newDiscoveryResolver(client), nil
+}
+
+func (r *discoveryResolver) resourcesFor(gvr schema.GroupVersionResource) ([]schema.GroupVersionResource, error) {
+	r.refreshOlderThan(discoveryRefreshInterval)
+	resolved, err := r.lookup(gvr)
+	if meta.IsNoMatchError(err) && r.refreshOlderThan(discoveryMissInterval) {
+		resolved, err = r.lookup(gvr)
+	}
+	if meta.IsNoMatchError(err) {
+		return nil, fmt.Errorf("%w: %s in %s", errResourceNotFound, gvr.Resource, getFormattedGV(gvr.Group, gvr.Version))
+	}
+	return resolved, err
+}
+
+// lookup resolves gvr against the cached discovery information. Categories
+// are only considered when no group or version is given and no resource has
+// the name.
+func (r *discoveryResolver) lookup(gvr schema.GroupVersionResource) ([]schema.GroupVersionResource, error) {
+	resolved, err := r.mapper.ResourceFor(gvr)
+	if err == nil {
+		return []schema.GroupVersionResource{resolved}, nil
+	}
+	if !meta.IsNoMatchError(err) || gvr.Group != "" || gvr.Version != "" {
+		return nil, err
+	}
+
+	members, ok := r.categories.Expand(gvr.Resource)
+	if !ok || len(members) == 0 {
+		return nil, err
+	}
+	var resources []schema.GroupVersionResource
+	for _, member := range members {
+		resource, err := r.mapper.ResourceFor(member.WithVersion(""))
+		if err != nil {
+			return nil, err
+		}
+		resources = append(resources, resource)
+	}
+	// Discovery does not list categories in a stable order
+	sort.Slice(resources, func(i, j int) bool {
+		return resources[i].String() < resources[j].String()
+	})
+	return resources, nil
+}
+
+func (r *discoveryResolver) kindFor(gvr schema.GroupVersionResource) (schema.GroupVersionKind, error) {
+	return r.mapper.KindFor(gvr)
+}
+
+// invalidate makes the next lookup refresh the cached discovery information.
+// Every subscription waiting for resources sees the same change, so changes
+// at or before the newest one invalidated already are ignored. Only resource
+// versions of CRDs and APIServices are passed in, which all come from the
+// same counter.
+func (r *discoveryResolver) invalidate(resourceVersion uint64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if resourceVersion != 0 && resourceVersion <= r.invalidatedVersion {
+		return
+	}
+	if resourceVersion > r.invalidatedVersion {
+		r.invalidatedVersion = resourceVersion
+	}
+	r.invalidated = r.now()
+}
+
+// refreshOlderThan drops the cached discovery information when it was
+// fetched longer than age ago or has been invalidated since, and reports
+// whether it did.
+func (r *discoveryResolver) refreshOlderThan(age time.Duration) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	if now.Sub(r.lastRefresh) < age && !r.lastRefresh.Before(r.invalidated) {
+		return false
+	}
+	r.lastRefresh = now
+	r.cache.Reset()
+	return true
+}
@@ -0,0 +1,181 @@
+package server
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+func newTestDiscovery() *fakediscovery.FakeDiscovery {
+	return &fakediscovery.FakeDiscovery{
+		Fake: &clienttesting.Fake{
+			Resources: []*metav1.APIResourceList{
+				{
+					GroupVersion: "v1",
+					APIResources: []metav1.APIResource{
+						{Name: "pods", SingularName: "pod", Kind: "Pod", Namespaced: true, ShortNames: []string{"po"}, Categories: []string{"all"}, Verbs: metav1.Verbs{"list", "watch"}},
+					},
+				},
+				{
+					GroupVersion: "apps/v1",
+					APIResources: []metav1.APIResource{
+						{Name: "deployments", SingularName: "deployment", Kind: "Deployment", Namespaced: true, ShortNames: []string{"deploy"}, Categories: []string{"all"}, Verbs: metav1.Verbs{"list", "watch"}},
+					},
+				},
+				{
+					GroupVersion: "apps/v1beta1",
+					APIResources: []metav1.APIResource{
+						{Name: "deployments", SingularName: "deployment", Kind: "Deployment", Namespaced: true, Verbs: metav1.Verbs{"list", "watch"}},
+					},
+				},
+			},
+		},
+	}
+}
+
+// newTestResolver returns a resolver over fake discovery whose clock is
+// advanced by the returned func.
+func newTestResolver(client *fakediscovery.FakeDiscovery) (*discoveryResolver, func(time.Duration)) {
+	resolver := newDiscoveryResolver(client)
+	now := time.Now()
+	resolver.now = func() time.Time { return now }
+	return resolver, func(d time.Duration) { now = now.Add(d) }
+}
+
+func TestDiscoveryResolver(t *testing.T) {
+	resolver, _ := newTestResolver(newTestDiscovery())
+
+	pods := schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+	deployments := schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+	tests := []struct {
+		name     string
+		input    schema.GroupVersionResource
+		expected []schema.GroupVersionResource
+	}{
+		{name: "Plural", input: schema.GroupVersionResource{Version: "v1", Resource: "pods"}, expected: []schema.GroupVersionResource{pods}},
+		{name: "Singular", input: schema.GroupVersionResource{Version: "v1", Resource: "pod"}, expected: []schema.GroupVersionResource{pods}},
+		{name: "Short name", input: schema.GroupVersionResource{Version: "v1", Resource: "po"}, expected: []schema.GroupVersionResource{pods}},
+		{name: "Group", input: schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployment"}, expected: []schema.GroupVersionResource{deployments}},
+		{name: "Any group", input: schema.GroupVersionResource{Version: "v1", Resource: "deploy"}, expected: []schema.GroupVersionResource{deployments}},
+		{name: "Preferred version", input: schema.GroupVersionResource{Resource: "deployment"}, expected: []schema.GroupVersionResource{deployments}},
+		{name: "Other version", input: schema.GroupVersionResource{Group: "apps", Version: "v1beta1", Resource: "deployment"}, expected: []schema.GroupVersionResource{deployments.GroupResource().WithVersion("v1beta1")}},
+		{name: "Category", input: schema.GroupVersionResource{Resource: "all"}, expected: []schema.GroupVersionResource{pods, deployments}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := resolver.resourcesFor(tc.input)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(actual, tc.expected) {
+				t.Errorf("expected: %v, got: %v", tc.expected, actual)
+			}
+		})
+	}
+
+	for _, gvr := range []schema.GroupVersionResource{
+		{Version: "v1", Resource: "nodes"},
+		// Categories are only expanded without a group or version
+		{Version: "v1", Resource: "all"},
+	} {
+		if _, err := resolver.resourcesFor(gvr); !errors.Is(err, errResourceNotFound) {
+			t.Errorf("Expected resource not found for %v, got %v", gvr, err)
+		}
+	}
+}
+
+func TestDiscoveryResolverKind(t *testing.T) {
+	resolver, _ := newTestResolver(newTestDiscovery())
+
+	kind, err := resolver.kindFor(schema.GroupVersionResource{Group: "apps", Version: "v1beta1", Resource: "deployments"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if expected := (schema.GroupVersionKind{Group: "apps", Version: "v1beta1", Kind: "Deployment"}); kind != expected {
+		t.Errorf("expected: %v, got: %v", expected, kind)
+	}
+}
+
+func TestDiscoveryResolverCache(t *testing.T) {
+	client := newTestDiscovery()
+	resolver, advance := newTestResolver(client)
+	pods := schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+
+	if _, err := resolver.resourcesFor(pods); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	calls := len(client.Actions())
+	if _, err := resolver.resourcesFor(pods); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(client.Actions()) != calls {
+		t.Errorf("Expected a cached lookup, got %d discovery calls", len(client.Actions())-calls)
+	}
+
+	// A miss only refreshes once the last refresh is old enough
+	client.Resources[0].APIResources = append(client.Resources[0].APIResources, metav1.APIResource{Name: "nodes", SingularName: "node", Kind: "Node", Verbs: metav1.Verbs{"list", "watch"}})
+	nodes := schema.GroupVersionResource{Version: "v1", Resource: "nodes"}
+	if _, err := resolver.resourcesFor(nodes); !errors.Is(err, errResourceNotFound) {
+		t.Errorf("Expected resource not found right after a refresh, got %v", err)
+	}
+	advance(discoveryMissInterval)
+	if _, err := resolver.resourcesFor(nodes); err != nil {
+		t.Errorf("Expected the miss to refresh discovery, got %v", err)
+	}
+
+	// Everything is refreshed periodically
+	client.Resources[0].APIResources = client.Resources[0].APIResources[:1]
+	advance(discoveryRefreshInterval)
+	if _, err := resolver.resourcesFor(nodes); !errors.Is(err, errResourceNotFound) {
+		t.Errorf("Expected the removed resource to be gone after a refresh, got %v", err)
+	}
+}
+
+func TestDiscoveryResolverInvalidate(t *testing.T) {
+	client := newTestDiscovery()
+	resolver, advance := newTestResolver(client)
+	nodes := schema.GroupVersionResource{Version: "v1", Resource: "nodes"}
+	if _, err := resolver.resourcesFor(nodes); !errors.Is(err, errResourceNotFound) {
+		t.Fatalf("Expected resource not found, got %v", err)
+	}
+
+	// A CRD being installed refreshes discovery right away
+	client.Resources[0].APIResources = append(client.Resources[0].APIResources, metav1.APIResource{Name: "nodes", SingularName: "node", Kind: "Node", Verbs: metav1.Verbs{"list", "watch"}})
+	advance(time.Second)
+	resolver.invalidate(2)
+	if _, err := resolver.resourcesFor(nodes); err != nil {
+		t.Errorf("Expected the invalidation to refresh discovery, got %v", err)
+	}
+
+	calls := len(client.Actions())
+	advance(time.Second)
+	if _, err := resolver.resourcesFor(nodes); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(client.Actions()) != calls {
+		t.Errorf("Expected one refresh per invalidation, got %d discovery calls", len(client.Actions())-calls)
+	}
+
+	// Other subscriptions seeing the same change do not refresh again, but
+	// a later change or one of unknown time does
+	for _, tc := range []struct {
+		resourceVersion uint64
+		refresh         bool
+	}{{2, false}, {1, false}, {3, true}, {0, true}} {
+		calls := len(client.Actions())
+		advance(time.Second)
+		resolver.invalidate(tc.resourceVersion)
+		if _, err := resolver.resourcesFor(nodes); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if refreshed := len(client.Actions()) != calls; refreshed != tc.refresh {
+			t.Errorf("Expected refresh for resourceVersion %d: %t, got %t", tc.resourceVersion, tc.refresh, refreshed)
+		}
+	}
+}
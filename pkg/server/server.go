@@ -1,10 +1,12 @@
 package server
 
 import (
-	"encoding/json"
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	"net"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -13,121 +15,259 @@ import (
 	"github.com/cmwylie19/watch-informer/pkg/logging"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
-	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/dynamic"
-	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/metadata"
 	"k8s.io/client-go/rest"
-	"k8s.io/client-go/tools/cache"
+)
+
+const (
+	syncPollInterval = 10 * time.Millisecond
+	// defaultSyncTimeout bounds how long a subscription waits for each of
+	// its informers to list.
+	defaultSyncTimeout = 30 * time.Second
 )
 
 type server struct {
 	api.UnimplementedWatchServiceServer
-	dynamicClient   dynamic.Interface
-	config          *rest.Config
-	Logger          logging.LoggerInterface
-	eventChans      map[string]chan *api.WatchResponse
-	mu              sync.Mutex
-	getResourceName func(*rest.Config, string, string, string) (string, error)
+	dynamicClient dynamic.Interface
+	config        *rest.Config
+	Logger        logging.LoggerInterface
+	sessions      map[uint64]*session
+	nextSessionID uint64
+	informers     *informerRegistry
+	deliveries    *deliveryRegistry
+	listSnapshots *listSnapshots
+	mu            sync.Mutex
+	resolver      resourceResolver
+	syncTimeout   time.Duration
 }
 
-func NewServer(dynamicClient dynamic.Interface, restConfig *rest.Config, logger logging.LoggerInterface) *server {
-	return &server{
-		dynamicClient:   dynamicClient,
-		eventChans:      make(map[string]chan *api.WatchResponse),
-		Logger:          logger,
-		config:          restConfig,
-		getResourceName: getResourceName,
+func NewServer(dynamicClient dynamic.Interface, restConfig *rest.Config, resyncPeriod time.Duration, logger logging.LoggerInterface) *server {
+	s := &server{
+		dynamicClient: dynamicClient,
+		sessions:      make(map[uint64]*session),
+		informers:     newInformerRegistry(dynamicClient, nil, resyncPeriod),
+		deliveries:    newDeliveryRegistry(deliveryRetention),
+		listSnapshots: newListSnapshots(listSnapshotRetention),
+		syncTimeout:   defaultSyncTimeout,
+		Logger:        logger,
+		config:        restConfig,
+	}
+
+	metadataClient, err := metadata.NewForConfig(restConfig)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to set up metadata-only watches: %v", err))
+	} else {
+		s.informers.metadataClient = metadataClient
 	}
-}
 
-func toJson(obj interface{}) string {
-	jsonData, err := json.Marshal(obj)
+	resolver, err := newDiscoveryResolverForConfig(restConfig)
 	if err != nil {
-		return fmt.Sprintf("Error converting to JSON: %v", err)
+		logger.Error(fmt.Sprintf("Failed to set up resource resolution: %v", err))
+		s.resolver = resolverFunc(func(schema.GroupVersionResource) ([]schema.GroupVersionResource, error) {
+			return nil, err
+		})
+		return s
 	}
-	return fmt.Sprintf("%v", string(jsonData))
+	s.resolver = resolver
+	return s
+}
+
+// eventStream is the server side of an RPC streaming WatchResponses.
+type eventStream interface {
+	Send(*api.WatchResponse) error
+	Context() context.Context
 }
 
 func (s *server) Watch(req *api.WatchRequest, srv api.WatchService_WatchServer) error {
 	req, err := s.formatRequest(req)
 	if err != nil {
 		s.Logger.Error(fmt.Sprintf("Failed to format request: %v", err))
+		return err
 	}
-	gvr := schema.GroupVersionResource{
-		Group:    req.Group,
-		Version:  req.Version,
-		Resource: req.Resource,
+	return s.serve(srv, req, req.DeliveryId, []*api.WatchRequest{req})
+}
+
+// WatchMany multiplexes the watches of several targets onto one stream.
+// Targets without an id are identified by their index.
+func (s *server) WatchMany(req *api.WatchManyRequest, srv api.WatchService_WatchManyServer) error {
+	if len(req.Targets) == 0 {
+		return invalidArgument("targets", "at least one target is required")
 	}
-	sessionId := formatSessionID(req)
+	ids := make(map[string]bool, len(req.Targets))
+	for i, target := range req.Targets {
+		field := fmt.Sprintf("targets[%d]", i)
+		if target == nil {
+			return invalidArgument(field, "target is empty")
+		}
+		if target.Id == "" {
+			target.Id = strconv.Itoa(i)
+		}
+		if ids[target.Id] {
+			return invalidArgument(field+".id", fmt.Sprintf("duplicate target id %q", target.Id))
+		}
+		ids[target.Id] = true
 
-	s.Logger.Info(fmt.Sprintf("Starting watch for %s", sessionId))
-	s.Logger.Debug(fmt.Sprintf("GVR: %v", gvr))
+		formatted, err := s.formatRequest(target)
+		if err != nil {
+			s.Logger.Error(fmt.Sprintf("Failed to format request: %v", err))
+			return withFieldPrefix(err, field+".")
+		}
+		req.Targets[i] = formatted
+	}
+	return s.serve(srv, req, req.DeliveryId, req.Targets)
+}
 
-	if s.dynamicClient == nil {
-		return fmt.Errorf("dynamic client is not initialized")
-	}
-	factory := dynamicinformer.NewFilteredDynamicSharedInformerFactory(s.dynamicClient, 5*time.Minute, req.Namespace, nil)
-	informer := factory.ForResource(gvr).Informer()
-
-	eventChan := make(chan *api.WatchResponse, 100)
-	s.mu.Lock()
-	s.eventChans[sessionId] = eventChan
-	s.mu.Unlock()
-
-	informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
-		AddFunc: func(obj interface{}) {
-			s.Logger.Debug(fmt.Sprintf("EventType: ADD, Details: %v", toJson(obj)))
-			select {
-			case eventChan <- &api.WatchResponse{EventType: "ADD", Details: toJson(obj)}:
-			default:
-				s.Logger.Error("Event channel is full, dropping event")
-			}
-		},
-		UpdateFunc: func(_, newObj interface{}) {
-			s.Logger.Debug(fmt.Sprintf("EventType: UPDATE, Details: %v", toJson(newObj)))
-			select {
-			case eventChan <- &api.WatchResponse{EventType: "UPDATE", Details: toJson(newObj)}:
-			default:
-				s.Logger.Error("Event channel is full, dropping event")
-			}
-		},
-		DeleteFunc: func(obj interface{}) {
-			s.Logger.Debug(fmt.Sprintf("EventType: DELETE, Details: %v", toJson(obj)))
-			select {
-			case eventChan <- &api.WatchResponse{EventType: "DELETE", Details: toJson(obj)}:
-			default:
-				s.Logger.Error("Event channel is full, dropping event")
-			}
-		},
-	})
+// Ack acknowledges the events of a delivery up to a sequence number.
+func (s *server) Ack(ctx context.Context, req *api.AckRequest) (*api.AckResponse, error) {
+	d, ok := s.deliveries.get(req.DeliveryId)
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "unknown delivery id %q", req.DeliveryId)
+	}
+	d.ack(req.Sequence)
+	return &api.AckResponse{}, nil
+}
 
+// serve subscribes a new session to every formatted target and streams its events
+// until the client goes away. A target that cannot be resumed gets an ERROR
+// event, and the stream ends once no target is left. With a deliveryID,
+// events are numbered and retained for redelivery until acked.
+func (s *server) serve(srv eventStream, opts queueOptions, deliveryID string, targets []*api.WatchRequest) error {
 	defer func() {
 		if r := recover(); r != nil {
 			s.Logger.Error(fmt.Sprint("Recovered in StartWatch", r))
 		}
 	}()
-	go informer.Run(make(chan struct{}))
 
-	go func() {
-		for event := range eventChan {
-			if err := srv.Send(event); err != nil {
+	if err := validateQueueOptions(opts); err != nil {
+		return err
+	}
+	if s.dynamicClient == nil {
+		return errClientNotInitialized
+	}
+
+	ctx, cancel := context.WithCancelCause(srv.Context())
+	defer cancel(nil)
+
+	var d *delivery
+	var detach func([]*api.WatchResponse)
+	if deliveryID != "" {
+		d, detach = s.deliveries.attach(deliveryID, cancel)
+	}
+
+	sess := s.openSession(opts)
+	defer s.endSession(sess, detach)
+
+	subscriptions := 0
+	for _, req := range targets {
+		sub, err := s.subscribe(ctx, sess, req)
+		if errors.Is(err, errResourceVersionExpired) {
+			s.Logger.Info(fmt.Sprintf("Cannot resume watch for %s from resourceVersion %s: %v", formatSessionID(req), req.ResourceVersion, err))
+			resp := newErrorResponse(apierrors.NewResourceExpired(err.Error()))
+			resp.SubscriptionId = req.Id
+			if err := srv.Send(resp); err != nil {
+				return err
+			}
+			continue
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if _, ok := status.FromError(err); ok {
+				return err
+			}
+			return status.Error(codes.Internal, err.Error())
+		}
+		defer s.unsubscribe(sub)
+		subscriptions++
+	}
+	if subscriptions == 0 {
+		return nil
+	}
+
+	err := s.sendEvents(ctx, srv, sess, d)
+	if cause := context.Cause(ctx); cause != nil && srv.Context().Err() == nil {
+		return cause
+	}
+	return err
+}
+
+// sendEvents drains the queue of sess onto srv until ctx is done. With a
+// delivery, unacked events are sent again first, and events are numbered and
+// held back while the client asks for it.
+func (s *server) sendEvents(ctx context.Context, srv eventStream, sess *session, d *delivery) error {
+	if d != nil {
+		for _, resp := range d.redeliveries() {
+			if err := srv.Send(resp); err != nil {
 				s.Logger.Error(fmt.Sprint("Failed to send event: ", err))
-				return
+				return err
 			}
 		}
-	}()
-	<-srv.Context().Done()
-	return srv.Context().Err()
+	}
+	for {
+		if d != nil {
+			if err := d.wait(ctx); err != nil {
+				return err
+			}
+		}
+		event, err := sess.events.next(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
+				s.Logger.Error(fmt.Sprintf("Stopping session %d: %v", sess.id, err))
+			}
+			return err
+		}
+		resp := s.toResponse(event)
+		if d != nil {
+			d.sent(resp)
+		}
+		if err := srv.Send(resp); err != nil {
+			s.Logger.Error(fmt.Sprint("Failed to send event: ", err))
+			return err
+		}
+	}
 }
 
-func StartGRPCServer(address string, dynamicClient dynamic.Interface, restConfig *rest.Config, logger logging.LoggerInterface) {
+// toResponse converts a queued event into the response sent for its
+// subscription.
+func (s *server) toResponse(event *queuedEvent) *api.WatchResponse {
+	if event.response != nil {
+		if event.subscription != nil {
+			event.response.SubscriptionId = event.subscription.id
+		}
+		s.Logger.Debug(fmt.Sprintf("EventType: %s, ResourceVersion: %s, Dropped: %d", event.response.EventType, event.response.ResourceVersion, event.response.Dropped))
+		return event.response
+	}
+
+	req := event.subscription.req
+	resp := newProjectedWatchResponse(event.eventType, event.obj, event.subscription.projection, event.kind)
+	resp.SubscriptionId = req.Id
+	switch event.eventType {
+	case api.EventType_ADD:
+		resp.IsInitialList = req.MarkInitialList && event.isInitialList
+	case api.EventType_UPDATE:
+		if err := addUpdateDetails(resp, event.subscription.projection.apply(event.oldObj), req); err != nil {
+			s.Logger.Error(fmt.Sprintf("Failed to add update details: %v", err))
+		}
+	}
+	s.Logger.Debug(fmt.Sprintf("EventType: %s, Details: %v", resp.EventType, resp.Details))
+	return resp
+}
+
+func StartGRPCServer(address string, dynamicClient dynamic.Interface, restConfig *rest.Config, resyncPeriod time.Duration, logger logging.LoggerInterface) {
 	lis, err := net.Listen("tcp", address)
 	if err != nil {
 		log.Fatalf("Failed to listen: %v", err)
 	}
-	s := NewServer(dynamicClient, restConfig, logger)
+	s := NewServer(dynamicClient, restConfig, resyncPeriod, logger)
 	grpcServer := grpc.NewServer()
 	api.RegisterWatchServiceServer(grpcServer, s)
 	reflection.Register(grpcServer)
@@ -139,16 +279,115 @@ func StartGRPCServer(address string, dynamicClient dynamic.Interface, restConfig
 }
 
 func (s *server) formatRequest(req *api.WatchRequest) (*api.WatchRequest, error) {
+	formatted, _, err := s.formatAndResolve(req)
+	return formatted, err
+}
+
+// formatAndResolve formats req like formatRequest and also returns the
+// resources it resolved to.
+func (s *server) formatAndResolve(req *api.WatchRequest) (*api.WatchRequest, []schema.GroupVersionResource, error) {
 	req.Resource = strings.ToLower(req.Resource)
+	if req.Resource == "" {
+		return nil, nil, invalidArgument("resource", "resource is required")
+	}
+
+	// Selectors are stored in canonical form so equivalent watches share an informer
+	labelSelector, err := labels.Parse(req.LabelSelector)
+	if err != nil {
+		return nil, nil, invalidArgument("label_selector", err.Error())
+	}
+	req.LabelSelector = labelSelector.String()
 
-	// Fetch the correct plural name dynamically
-	resourceName, err := s.getResourceName(s.config, req.Group, req.Version, req.Resource)
+	fieldSelector, err := fields.ParseSelector(req.FieldSelector)
 	if err != nil {
-		return nil, err
+		return nil, nil, invalidArgument("field_selector", err.Error())
 	}
+	req.FieldSelector = fieldSelector.String()
 
-	req.Resource = resourceName
-	return req, nil
+	if req.ResourceVersion != "" {
+		if _, err := parseResourceVersion(req.ResourceVersion); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if req.Namespace != "" && (len(req.Namespaces) > 0 || req.NamespaceLabelSelector != "") {
+		return nil, nil, invalidArgument("namespace", "namespace cannot be combined with namespaces or a namespace label selector")
+	}
+	var namespaces []string
+	seen := make(map[string]bool, len(req.Namespaces))
+	for i, namespace := range req.Namespaces {
+		if namespace == "" {
+			return nil, nil, invalidArgument(fmt.Sprintf("namespaces[%d]", i), "namespace cannot be empty")
+		}
+		if !seen[namespace] {
+			seen[namespace] = true
+			namespaces = append(namespaces, namespace)
+		}
+	}
+	req.Namespaces = namespaces
+
+	if req.NamespaceLabelSelector != "" {
+		namespaceSelector, err := labels.Parse(req.NamespaceLabelSelector)
+		if err != nil {
+			return nil, nil, invalidArgument("namespace_label_selector", err.Error())
+		}
+		req.NamespaceLabelSelector = namespaceSelector.String()
+	}
+
+	if req.Filter != "" {
+		if _, err := compileFilter(req.Filter); err != nil {
+			return nil, nil, err
+		}
+	}
+	if err := validatePaths("fields", req.Fields); err != nil {
+		return nil, nil, err
+	}
+	if err := validatePaths("ignore_paths", req.IgnorePaths); err != nil {
+		return nil, nil, err
+	}
+	if err := s.validateResyncs(req); err != nil {
+		return nil, nil, err
+	}
+
+	// Resolve the resource to the group, version and plural name the API
+	// server serves. Categories, and resources that are waited for, are kept
+	// by name and resolved again when subscribing.
+	resolved, err := s.resolve(req)
+	if err != nil && !waitsForResource(req, err) {
+		return nil, nil, err
+	}
+	if len(resolved) == 1 {
+		req.Group = resolved[0].Group
+		req.Version = resolved[0].Version
+		req.Resource = resolved[0].Resource
+	}
+	return req, resolved, nil
+}
+
+// validateResyncs rejects resyncs the informers cannot deliver: none when
+// the server disables them, and none more often than the server's period.
+func (s *server) validateResyncs(req *api.WatchRequest) error {
+	if !req.IncludeResyncs {
+		return nil
+	}
+	serverPeriod := s.informers.resyncPeriod
+	if serverPeriod == 0 {
+		return invalidArgument("include_resyncs", "resyncs are disabled on this server")
+	}
+	if period := time.Duration(req.ResyncPeriodSeconds) * time.Second; period > 0 && period < serverPeriod {
+		return invalidArgument("resync_period_seconds", fmt.Sprintf("must be at least the server's resync period of %s", serverPeriod))
+	}
+	return nil
+}
+
+// resolve returns the resources named by req.
+func (s *server) resolve(req *api.WatchRequest) ([]schema.GroupVersionResource, error) {
+	gvr := schema.GroupVersionResource{Group: req.Group, Version: req.Version, Resource: req.Resource}
+	resolved, err := s.resolver.resourcesFor(gvr)
+	if err != nil {
+		return nil, resolveError(gvr, err)
+	}
+	return resolved, nil
 }
 
 func formatSessionID(req *api.WatchRequest) string {
@@ -164,27 +403,26 @@ func formatSessionID(req *api.WatchRequest) string {
 		group = req.Group
 	}
 
-	return fmt.Sprintf("Group: %s, Version: %s, Resource: %s, Namespace: %s", group, req.Version, req.Resource, namespace)
-}
-
-func getResourceName(restConfig *rest.Config, group, version, resource string) (string, error) {
-	discoveryClient, err := discovery.NewDiscoveryClientForConfig(restConfig)
-	if err != nil {
-		return "", fmt.Errorf("failed to create discovery client: %w", err)
+	sessionID := fmt.Sprintf("Group: %s, Version: %s, Resource: %s, Namespace: %s", group, req.Version, req.Resource, namespace)
+	if req.LabelSelector != "" {
+		sessionID += fmt.Sprintf(", LabelSelector: %s", req.LabelSelector)
 	}
-	formattedGV := getFormattedGV(group, version)
-	resourceList, err := discoveryClient.ServerResourcesForGroupVersion(formattedGV)
-	if err != nil {
-		return "", fmt.Errorf("failed to fetch resource list: %w", err)
+	if req.FieldSelector != "" {
+		sessionID += fmt.Sprintf(", FieldSelector: %s", req.FieldSelector)
 	}
-
-	for _, apiResource := range resourceList.APIResources {
-		if apiResource.SingularName == resource || apiResource.Name == resource {
-			return apiResource.Name, nil
-		}
+	if len(req.Namespaces) > 0 {
+		sessionID += fmt.Sprintf(", Namespaces: %s", strings.Join(req.Namespaces, ","))
 	}
-
-	return "", fmt.Errorf("resource not found: %s", resource)
+	if req.NamespaceLabelSelector != "" {
+		sessionID += fmt.Sprintf(", NamespaceLabelSelector: %s", req.NamespaceLabelSelector)
+	}
+	if req.Filter != "" {
+		sessionID += fmt.Sprintf(", Filter: %s", req.Filter)
+	}
+	if req.MetadataOnly {
+		sessionID += ", MetadataOnly: true"
+	}
+	return sessionID
 }
 
 func getFormattedGV(group, version string) string {
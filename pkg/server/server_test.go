@@ -1,18 +1,115 @@
 package server
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/fake"
+	clienttesting "k8s.io/client-go/testing"
 
 	"github.com/cmwylie19/watch-informer/api"
 	"github.com/cmwylie19/watch-informer/mocks"
 	"k8s.io/client-go/rest"
 )
 
+func newTestPod(namespace, name string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Pod",
+		"metadata": map[string]interface{}{
+			"namespace":       namespace,
+			"name":            name,
+			"resourceVersion": "1",
+		},
+	}}
+}
+
+func newTestWatchServer(ctrl *gomock.Controller, objects ...runtime.Object) *server {
+	mockLogger := mocks.NewMockLoggerInterface(ctrl)
+	mockLogger.EXPECT().Info(gomock.Any()).AnyTimes()
+	mockLogger.EXPECT().Debug(gomock.Any()).AnyTimes()
+	mockLogger.EXPECT().Warn(gomock.Any()).AnyTimes()
+	mockLogger.EXPECT().Error(gomock.Any()).AnyTimes()
+
+	s := NewServer(newFakeDynamicClient(objects...), &rest.Config{}, DefaultResyncPeriod, mockLogger)
+	// Released informers stop right away unless a test keeps them
+	s.informers.idleTimeout = 0
+	s.resolver = resolverFunc(func(gvr schema.GroupVersionResource) ([]schema.GroupVersionResource, error) {
+		gvr.Resource = "pods"
+		return []schema.GroupVersionResource{gvr}, nil
+	})
+	return s
+}
+
+// startTestWatch runs Watch in the background and returns the events sent to
+// the stream, a cancel func ending the stream and a channel with its result.
+func startTestWatch(ctrl *gomock.Controller, s *server, req *api.WatchRequest) (<-chan *api.WatchResponse, context.CancelFunc, <-chan error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	events := make(chan *api.WatchResponse, 100)
+
+	stream := mocks.NewMockWatchService_WatchServer(ctrl)
+	stream.EXPECT().Context().Return(ctx).AnyTimes()
+	stream.EXPECT().Send(gomock.Any()).DoAndReturn(func(event *api.WatchResponse) error {
+		events <- event
+		return nil
+	}).AnyTimes()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- s.Watch(req, stream)
+	}()
+	return events, cancel, done
+}
+
+func waitForEvent(t *testing.T, events <-chan *api.WatchResponse) *api.WatchResponse {
+	t.Helper()
+	select {
+	case event := <-events:
+		return event
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Timed out waiting for event")
+		return nil
+	}
+}
+
+func waitForEventType(t *testing.T, events <-chan *api.WatchResponse, eventType api.EventType) *api.WatchResponse {
+	t.Helper()
+	event := waitForEvent(t, events)
+	if event.Type != eventType {
+		t.Fatalf("Expected %s event, got %s", eventType, event.Type)
+	}
+	return event
+}
+
+func waitForCondition(t *testing.T, condition func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatalf("Timed out waiting for condition")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
 func TestNewServer(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -21,7 +118,7 @@ func TestNewServer(t *testing.T) {
 
 	var mockClient dynamic.Interface
 	mockRestConfig := &rest.Config{}
-	s := NewServer(mockClient, mockRestConfig, mockLogger)
+	s := NewServer(mockClient, mockRestConfig, time.Minute, mockLogger)
 
 	if s == nil {
 		t.Errorf("Expected server object, got nil")
@@ -35,6 +132,10 @@ func TestNewServer(t *testing.T) {
 		t.Errorf("Expected restConfig to be set")
 	}
 
+	if s.informers.resyncPeriod != time.Minute {
+		t.Errorf("Expected resyncPeriod to be set")
+	}
+
 	if s.Logger != mockLogger {
 		t.Errorf("Expected logger to be set")
 	}
@@ -61,6 +162,26 @@ func TestFormSessionID(t *testing.T) {
 			inputReq: &api.WatchRequest{Group: "v1"},
 			expected: "Group: v1, Version: , Resource: , Namespace: *",
 		},
+		{
+			name:     "Selectors",
+			inputReq: &api.WatchRequest{Version: "v1", Resource: "pods", Namespace: "default", LabelSelector: "app=nginx", FieldSelector: "status.phase=Running"},
+			expected: "Group: '', Version: v1, Resource: pods, Namespace: default, LabelSelector: app=nginx, FieldSelector: status.phase=Running",
+		},
+		{
+			name:     "Namespaces",
+			inputReq: &api.WatchRequest{Version: "v1", Resource: "pods", Namespaces: []string{"a", "b"}, NamespaceLabelSelector: "team=payments"},
+			expected: "Group: '', Version: v1, Resource: pods, Namespace: *, Namespaces: a,b, NamespaceLabelSelector: team=payments",
+		},
+		{
+			name:     "Filter",
+			inputReq: &api.WatchRequest{Version: "v1", Resource: "pods", Filter: `object.status.phase == "Failed"`},
+			expected: `Group: '', Version: v1, Resource: pods, Namespace: *, Filter: object.status.phase == "Failed"`,
+		},
+		{
+			name:     "Metadata only",
+			inputReq: &api.WatchRequest{Version: "v1", Resource: "pods", MetadataOnly: true},
+			expected: "Group: '', Version: v1, Resource: pods, Namespace: *, MetadataOnly: true",
+		},
 	}
 
 	for _, tc := range tests {
@@ -105,7 +226,7 @@ func TestGetFormattedGV(t *testing.T) {
 }
 
 func TestFormatRequest(t *testing.T) {
-	mockGetResourceName := func(_ *rest.Config, group, version, resource string) (string, error) {
+	mockResolver := func(gvr schema.GroupVersionResource) ([]schema.GroupVersionResource, error) {
 		mockResources := map[string]string{
 			"pod":         "pods",
 			"POD":         "pods",
@@ -113,15 +234,16 @@ func TestFormatRequest(t *testing.T) {
 			"services":    "services",
 		}
 
-		if plural, exists := mockResources[resource]; exists {
-			return plural, nil
+		if plural, exists := mockResources[gvr.Resource]; exists {
+			gvr.Resource = plural
+			return []schema.GroupVersionResource{gvr}, nil
 		}
-		return "", fmt.Errorf("resource not found: %s", resource)
+		return nil, fmt.Errorf("%w: %s", errResourceNotFound, gvr.Resource)
 	}
 
 	mockServer := &server{
-		config:          nil,                 // Not needed for mock
-		getResourceName: mockGetResourceName, // ✅ Inject mock function
+		config:   nil,                        // Not needed for mock
+		resolver: resolverFunc(mockResolver), // ✅ Inject mock function
 	}
 
 	tests := []struct {
@@ -154,6 +276,48 @@ func TestFormatRequest(t *testing.T) {
 			expected: nil,
 			wantErr:  true,
 		},
+		{
+			name:     "Selectors in canonical form",
+			inputReq: &api.WatchRequest{Resource: "pod", LabelSelector: "tier in (web), app=nginx", FieldSelector: "status.phase=Running"},
+			expected: &api.WatchRequest{Resource: "pods", LabelSelector: "app=nginx,tier in (web)", FieldSelector: "status.phase=Running"},
+			wantErr:  false,
+		},
+		{
+			name:     "Invalid label selector",
+			inputReq: &api.WatchRequest{Resource: "pod", LabelSelector: "app in (web"},
+			expected: nil,
+			wantErr:  true,
+		},
+		{
+			name:     "Invalid field selector",
+			inputReq: &api.WatchRequest{Resource: "pod", FieldSelector: "status.phase"},
+			expected: nil,
+			wantErr:  true,
+		},
+		{
+			name:     "Duplicate namespaces",
+			inputReq: &api.WatchRequest{Resource: "pod", Namespaces: []string{"a", "b", "a"}, NamespaceLabelSelector: "team = payments"},
+			expected: &api.WatchRequest{Resource: "pods", Namespaces: []string{"a", "b"}, NamespaceLabelSelector: "team=payments"},
+			wantErr:  false,
+		},
+		{
+			name:     "Namespace with namespaces",
+			inputReq: &api.WatchRequest{Resource: "pod", Namespace: "a", Namespaces: []string{"b"}},
+			expected: nil,
+			wantErr:  true,
+		},
+		{
+			name:     "Empty namespace in namespaces",
+			inputReq: &api.WatchRequest{Resource: "pod", Namespaces: []string{"a", ""}},
+			expected: nil,
+			wantErr:  true,
+		},
+		{
+			name:     "Invalid namespace label selector",
+			inputReq: &api.WatchRequest{Resource: "pod", NamespaceLabelSelector: "team in (payments"},
+			expected: nil,
+			wantErr:  true,
+		},
 	}
 
 	for _, tc := range tests {
@@ -170,3 +334,884 @@ func TestFormatRequest(t *testing.T) {
 		})
 	}
 }
+
+func TestWatchSessionLifecycle(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := newTestWatchServer(ctrl, newTestPod("default", "nginx"))
+	req := func() *api.WatchRequest {
+		return &api.WatchRequest{Version: "v1", Resource: "pod", Namespace: "default"}
+	}
+
+	firstEvents, cancelFirst, firstDone := startTestWatch(ctrl, s, req())
+	secondEvents, cancelSecond, secondDone := startTestWatch(ctrl, s, req())
+
+	for _, events := range []<-chan *api.WatchResponse{firstEvents, secondEvents} {
+		waitForEventType(t, events, api.EventType_RESOLVED)
+		if event := waitForEvent(t, events); event.Type != api.EventType_ADD {
+			t.Errorf("Expected ADD event, got %s", event.EventType)
+		}
+	}
+	if s.sessionCount() != 2 {
+		t.Errorf("Expected 2 live sessions, got %d", s.sessionCount())
+	}
+	if s.informers.len() != 1 {
+		t.Errorf("Expected 1 shared informer, got %d", s.informers.len())
+	}
+
+	cancelFirst()
+	if err := <-firstDone; err != context.Canceled {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	if s.sessionCount() != 1 {
+		t.Errorf("Expected 1 live session, got %d", s.sessionCount())
+	}
+	if s.informers.len() != 1 {
+		t.Errorf("Expected informer to outlive the first session, got %d", s.informers.len())
+	}
+
+	cancelSecond()
+	<-secondDone
+	if s.sessionCount() != 0 {
+		t.Errorf("Expected no live sessions, got %d", s.sessionCount())
+	}
+	if s.informers.len() != 0 {
+		t.Errorf("Expected no running informers, got %d", s.informers.len())
+	}
+}
+
+func TestSessionClose(t *testing.T) {
+	sess := newSession(1, &api.WatchRequest{})
+	if !sess.push(&queuedEvent{eventType: api.EventType_ADD, obj: newTestPod("default", "nginx")}) {
+		t.Fatalf("Expected push to succeed on an open session")
+	}
+
+	sess.close()
+	sess.close()
+
+	if _, err := sess.events.next(context.Background()); err != errQueueClosed {
+		t.Errorf("Expected queue to be drained and closed, got %v", err)
+	}
+	if sess.push(&queuedEvent{eventType: api.EventType_ADD, obj: newTestPod("default", "nginx")}) {
+		t.Errorf("Expected push to fail on a closed session")
+	}
+}
+
+func TestWatchResumeFromResourceVersion(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := newTestWatchServer(ctrl, newTestPod("default", "nginx"))
+	firstEvents, cancelFirst, firstDone := startTestWatch(ctrl, s, &api.WatchRequest{Version: "v1", Resource: "pod", Namespace: "default"})
+	defer func() {
+		cancelFirst()
+		<-firstDone
+	}()
+	waitForEventType(t, firstEvents, api.EventType_RESOLVED)
+	waitForEventType(t, firstEvents, api.EventType_ADD)
+	waitForEventType(t, firstEvents, api.EventType_SYNCED)
+
+	updated := newTestPod("default", "nginx")
+	updated.SetResourceVersion("2")
+	updated.SetLabels(map[string]string{"app": "nginx"})
+	if _, err := s.dynamicClient.Resource(podsGVR).Namespace("default").Update(context.Background(), updated, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("Failed to update pod: %v", err)
+	}
+	if event := waitForEvent(t, firstEvents); event.Type != api.EventType_UPDATE {
+		t.Fatalf("Expected UPDATE event, got %s", event.Type)
+	}
+
+	key := formatSessionID(&api.WatchRequest{Version: "v1", Resource: "pods", Namespace: "default"})
+	shared := mustAcquire(t, s.informers, key, "default")
+	defer s.informers.release(key)
+	waitForCondition(t, func() bool {
+		shared.history.mu.Lock()
+		defer shared.history.mu.Unlock()
+		return len(shared.history.events) == 1
+	})
+
+	resumedEvents, cancelResumed, resumedDone := startTestWatch(ctrl, s, &api.WatchRequest{Version: "v1", Resource: "pod", Namespace: "default", ResourceVersion: "1"})
+	waitForEventType(t, resumedEvents, api.EventType_RESOLVED)
+	event := waitForEvent(t, resumedEvents)
+	if event.Type != api.EventType_UPDATE || event.ResourceVersion != "2" {
+		t.Errorf("Expected replayed UPDATE at resourceVersion 2, got %s at %s: %s", event.Type, event.ResourceVersion, event.Details)
+	}
+	waitForEventType(t, resumedEvents, api.EventType_SYNCED)
+	cancelResumed()
+	<-resumedDone
+
+	select {
+	case event := <-resumedEvents:
+		t.Errorf("Expected no initial list replay, got %s", event.Type)
+	default:
+	}
+}
+
+func TestWatchResumeAfterDisconnect(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := newTestWatchServer(ctrl, newTestPod("default", "nginx"))
+	s.informers.idleTimeout = time.Minute
+	listAtResourceVersion(s.dynamicClient.(*fake.FakeDynamicClient), podsGVR, "10")
+	req := &api.WatchRequest{Version: "v1", Resource: "pod", Namespace: "default"}
+	events, cancel, done := startTestWatch(ctrl, s, req)
+	waitForEventType(t, events, api.EventType_RESOLVED)
+	waitForEventType(t, events, api.EventType_ADD)
+	if event := waitForEventType(t, events, api.EventType_SYNCED); event.ResourceVersion != "10" {
+		t.Errorf("Expected SYNCED at the list's resourceVersion 10, got %q", event.ResourceVersion)
+	}
+
+	pods := s.dynamicClient.Resource(podsGVR).Namespace("default")
+	updated := newTestPod("default", "nginx")
+	updated.SetResourceVersion("11")
+	if _, err := pods.Update(context.Background(), updated, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("Failed to update pod: %v", err)
+	}
+	waitForEventType(t, events, api.EventType_UPDATE)
+
+	// The only client goes away and the pod changes before it is back
+	cancel()
+	<-done
+	if s.informers.len() != 1 {
+		t.Fatalf("Expected the informer to outlive its last subscriber, got %d informers", s.informers.len())
+	}
+	updated.SetResourceVersion("12")
+	updated.SetLabels(map[string]string{"app": "nginx"})
+	if _, err := pods.Update(context.Background(), updated, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("Failed to update pod: %v", err)
+	}
+	shared, _ := s.informers.get(formatSessionID(&api.WatchRequest{Version: "v1", Resource: "pods", Namespace: "default"}))
+	waitForCondition(t, func() bool { return shared.history.appliedVersion() == "12" })
+
+	resumed := &api.WatchRequest{Version: "v1", Resource: "pod", Namespace: "default", ResourceVersion: "11"}
+	events, cancel, done = startTestWatch(ctrl, s, resumed)
+	defer func() {
+		cancel()
+		<-done
+	}()
+	waitForEventType(t, events, api.EventType_RESOLVED)
+	if event := waitForEvent(t, events); event.Type != api.EventType_UPDATE || event.ResourceVersion != "12" {
+		t.Errorf("Expected the missed UPDATE at resourceVersion 12, got %s at %s", event.Type, event.ResourceVersion)
+	}
+	waitForEventType(t, events, api.EventType_SYNCED)
+}
+
+func TestWatchResumeExpired(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := newTestWatchServer(ctrl, newTestPod("default", "nginx"))
+	listAtResourceVersion(s.dynamicClient.(*fake.FakeDynamicClient), podsGVR, "10")
+	events, cancel, done := startTestWatch(ctrl, s, &api.WatchRequest{Version: "v1", Resource: "pod", ResourceVersion: "5"})
+	defer cancel()
+
+	event := waitForEvent(t, events)
+	if event.Type != api.EventType_ERROR {
+		t.Fatalf("Expected ERROR event, got %s", event.Type)
+	}
+
+	var status metav1.Status
+	if err := json.Unmarshal(event.Object, &status); err != nil {
+		t.Fatalf("Expected a Status object: %v", err)
+	}
+	if status.Code != http.StatusGone || status.Reason != metav1.StatusReasonExpired {
+		t.Errorf("Expected 410 Expired, got %d %s", status.Code, status.Reason)
+	}
+	if err := <-done; err != nil {
+		t.Errorf("Expected stream to end cleanly, got %v", err)
+	}
+	if s.sessionCount() != 0 || s.informers.len() != 0 {
+		t.Errorf("Expected session and informer to be released, got %d and %d", s.sessionCount(), s.informers.len())
+	}
+}
+
+func TestWatchListRefused(t *testing.T) {
+	tests := []struct {
+		name       string
+		listErr    error
+		code       codes.Code
+		statusCode int32
+	}{
+		{
+			name:       "Forbidden",
+			listErr:    apierrors.NewForbidden(podsGVR.GroupResource(), "", errors.New("no RBAC rule")),
+			code:       codes.PermissionDenied,
+			statusCode: http.StatusForbidden,
+		},
+		{
+			name:       "Bad request",
+			listErr:    apierrors.NewBadRequest("unable to parse requested label selector"),
+			code:       codes.InvalidArgument,
+			statusCode: http.StatusBadRequest,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			s := newTestWatchServer(ctrl, newTestPod("default", "nginx"))
+			s.dynamicClient.(*fake.FakeDynamicClient).PrependReactor("list", "pods", func(clienttesting.Action) (bool, runtime.Object, error) {
+				return true, nil, tc.listErr
+			})
+			req := &api.WatchRequest{Version: "v1", Resource: "pod", Namespace: "default"}
+			resumed := &api.WatchRequest{Version: "v1", Resource: "pod", Namespace: "default", ResourceVersion: "1"}
+			watches := []struct {
+				name  string
+				start func() (<-chan *api.WatchResponse, context.CancelFunc, <-chan error)
+			}{
+				{name: "Watch", start: func() (<-chan *api.WatchResponse, context.CancelFunc, <-chan error) {
+					return startTestWatch(ctrl, s, req)
+				}},
+				{name: "Watch resumed", start: func() (<-chan *api.WatchResponse, context.CancelFunc, <-chan error) {
+					return startTestWatch(ctrl, s, resumed)
+				}},
+				{name: "WatchMany", start: func() (<-chan *api.WatchResponse, context.CancelFunc, <-chan error) {
+					return startTestWatchMany(ctrl, s, &api.WatchManyRequest{Targets: []*api.WatchRequest{req}})
+				}},
+			}
+			for _, watch := range watches {
+				_, cancel, done := watch.start()
+				select {
+				case err := <-done:
+					if status.Code(err) != tc.code {
+						t.Errorf("%s: expected %s, got %v", watch.name, tc.code, err)
+					}
+				case <-time.After(5 * time.Second):
+					t.Fatalf("%s: timed out waiting for the watch to fail", watch.name)
+				}
+				cancel()
+			}
+
+			requests, events, cancel, done := startTestWatchStream(ctrl, s)
+			defer func() {
+				cancel()
+				<-done
+			}()
+			requests <- subscribeRequest(&api.WatchRequest{Version: "v1", Resource: "pod", Namespace: "default", Id: "pods"})
+			// The resource resolves before its list fails
+			event := waitForEvent(t, events)
+			if event.Type == api.EventType_RESOLVED {
+				event = waitForEvent(t, events)
+			}
+			if event.Type != api.EventType_ERROR {
+				t.Fatalf("Expected ERROR event, got %s", event.Type)
+			}
+			var st metav1.Status
+			if err := json.Unmarshal(event.Object, &st); err != nil {
+				t.Fatalf("Expected a Status object: %v", err)
+			}
+			if event.SubscriptionId != "pods" || st.Code != tc.statusCode {
+				t.Errorf("Expected %d for subscription pods, got %d for %q", tc.statusCode, st.Code, event.SubscriptionId)
+			}
+			waitForCondition(t, func() bool { return s.informers.len() == 0 })
+		})
+	}
+}
+
+func TestWatchUnsupportedFieldLabel(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Like the API server, only some field labels are supported
+	s := newTestWatchServer(ctrl, newTestPod("default", "nginx"))
+	s.dynamicClient.(*fake.FakeDynamicClient).PrependReactor("list", "pods", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		for _, requirement := range action.(clienttesting.ListAction).GetListRestrictions().Fields.Requirements() {
+			if requirement.Field != "metadata.name" && requirement.Field != "status.phase" {
+				return true, nil, apierrors.NewBadRequest(fmt.Sprintf("field label not supported: %s", requirement.Field))
+			}
+		}
+		return false, nil, nil
+	})
+	_, cancel, done := startTestWatch(ctrl, s, &api.WatchRequest{Version: "v1", Resource: "pod", Namespace: "default", FieldSelector: "spec.color=blue"})
+	defer cancel()
+
+	select {
+	case err := <-done:
+		st := status.Convert(err)
+		if st.Code() != codes.InvalidArgument {
+			t.Fatalf("Expected InvalidArgument, got %v", err)
+		}
+		assertStatusDetails(t, st, "field_selector", "")
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for the watch to fail")
+	}
+	waitForCondition(t, func() bool { return s.informers.len() == 0 })
+}
+
+func TestWatchLabelSelector(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	matching := newTestPod("default", "nginx")
+	matching.SetLabels(map[string]string{"app": "nginx"})
+	s := newTestWatchServer(ctrl, matching, newTestPod("default", "redis"))
+
+	filteredEvents, cancelFiltered, filteredDone := startTestWatch(ctrl, s, &api.WatchRequest{Version: "v1", Resource: "pod", Namespace: "default", LabelSelector: "app=nginx"})
+	allEvents, cancelAll, allDone := startTestWatch(ctrl, s, &api.WatchRequest{Version: "v1", Resource: "pod", Namespace: "default"})
+
+	waitForEventType(t, filteredEvents, api.EventType_RESOLVED)
+	if event := waitForEvent(t, filteredEvents); event.Name != "nginx" {
+		t.Errorf("Expected only the matching pod, got %s", event.Name)
+	}
+	waitForEventType(t, filteredEvents, api.EventType_SYNCED)
+	waitForEventType(t, allEvents, api.EventType_RESOLVED)
+	waitForEvent(t, allEvents)
+	waitForEvent(t, allEvents)
+	if s.informers.len() != 2 {
+		t.Errorf("Expected separate informers for different selectors, got %d", s.informers.len())
+	}
+
+	cancelFiltered()
+	cancelAll()
+	<-filteredDone
+	<-allDone
+	select {
+	case event := <-filteredEvents:
+		t.Errorf("Expected no further events for the selector, got %s", event.Name)
+	default:
+	}
+}
+
+func TestWatchInitialSync(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := newTestWatchServer(ctrl, newTestPod("default", "nginx"))
+	events, cancel, done := startTestWatch(ctrl, s, &api.WatchRequest{Version: "v1", Resource: "pod", Namespace: "default", MarkInitialList: true})
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	waitForEventType(t, events, api.EventType_RESOLVED)
+	if event := waitForEventType(t, events, api.EventType_ADD); !event.IsInitialList {
+		t.Errorf("Expected initial list ADD to be marked")
+	}
+	waitForEventType(t, events, api.EventType_SYNCED)
+
+	if _, err := s.dynamicClient.Resource(podsGVR).Namespace("default").Create(context.Background(), newTestPod("default", "redis"), metav1.CreateOptions{}); err != nil {
+		t.Fatalf("Failed to create pod: %v", err)
+	}
+	if event := waitForEventType(t, events, api.EventType_ADD); event.IsInitialList {
+		t.Errorf("Expected new ADD not to be marked as initial list")
+	}
+}
+
+func TestWatchCategory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	deployment := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   map[string]interface{}{"namespace": "default", "name": "web", "resourceVersion": "1"},
+	}}
+	s := newTestWatchServer(ctrl, newTestPod("default", "nginx"), deployment)
+	s.resolver = resolverFunc(func(gvr schema.GroupVersionResource) ([]schema.GroupVersionResource, error) {
+		return []schema.GroupVersionResource{podsGVR, deploymentsGVR}, nil
+	})
+	events, cancel, done := startTestWatch(ctrl, s, &api.WatchRequest{Resource: "all", Namespace: "default"})
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	resolved := waitForEventType(t, events, api.EventType_RESOLVED)
+	expected := []*api.GroupVersionResource{
+		{Version: "v1", Resource: "pods"},
+		{Group: "apps", Version: "v1", Resource: "deployments"},
+	}
+	if len(resolved.Resources) != len(expected) {
+		t.Fatalf("expected: %v, got: %v", expected, resolved.Resources)
+	}
+	for i := range expected {
+		if !proto.Equal(expected[i], resolved.Resources[i]) {
+			t.Errorf("expected: %v, got: %v", expected[i], resolved.Resources[i])
+		}
+	}
+
+	received := map[string]bool{}
+	for i := 0; i < 2; i++ {
+		event := waitForEventType(t, events, api.EventType_ADD)
+		received[event.Kind+"/"+event.Name] = true
+	}
+	if !received["Pod/nginx"] || !received["Deployment/web"] {
+		t.Errorf("Expected the pods and deployments of the category, got %v", received)
+	}
+	waitForEventType(t, events, api.EventType_SYNCED)
+	if s.informers.len() != 2 {
+		t.Errorf("Expected an informer per resource, got %d", s.informers.len())
+	}
+}
+
+func TestWatchDisconnectOnOverflow(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := newTestWatchServer(ctrl, newTestPod("default", "nginx"))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// The stream is stuck sending the first ADD until the queue overflows.
+	sending := make(chan struct{}, 10)
+	blocked := make(chan struct{})
+	stream := mocks.NewMockWatchService_WatchServer(ctrl)
+	stream.EXPECT().Context().Return(ctx).AnyTimes()
+	stream.EXPECT().Send(gomock.Any()).DoAndReturn(func(resp *api.WatchResponse) error {
+		if resp.Type == api.EventType_RESOLVED {
+			return nil
+		}
+		sending <- struct{}{}
+		<-blocked
+		return nil
+	}).AnyTimes()
+	defer close(blocked)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- s.Watch(&api.WatchRequest{Version: "v1", Resource: "pod", Namespace: "default", BufferSize: 1, BackpressurePolicy: api.BackpressurePolicy_DISCONNECT}, stream)
+	}()
+
+	select {
+	case <-sending:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Timed out waiting for the first send")
+	}
+	for _, name := range []string{"redis", "busybox"} {
+		if _, err := s.dynamicClient.Resource(podsGVR).Namespace("default").Create(context.Background(), newTestPod("default", name), metav1.CreateOptions{}); err != nil {
+			t.Fatalf("Failed to create pod: %v", err)
+		}
+	}
+	waitForCondition(t, func() bool {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		for _, sess := range s.sessions {
+			sess.events.mu.Lock()
+			defer sess.events.mu.Unlock()
+			return sess.events.err != nil
+		}
+		return false
+	})
+	blocked <- struct{}{}
+
+	select {
+	case err := <-done:
+		if status.Code(err) != codes.ResourceExhausted {
+			t.Errorf("Expected ResourceExhausted, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Timed out waiting for the stream to end")
+	}
+	if s.sessionCount() != 0 {
+		t.Errorf("Expected no live sessions, got %d", s.sessionCount())
+	}
+}
+
+func TestWatchBlockedEndsWithStream(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := newTestWatchServer(ctrl, newTestPod("default", "nginx"))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// The stream is stuck sending the first ADD until the client goes away.
+	sending := make(chan struct{}, 10)
+	stream := mocks.NewMockWatchService_WatchServer(ctrl)
+	stream.EXPECT().Context().Return(ctx).AnyTimes()
+	stream.EXPECT().Send(gomock.Any()).DoAndReturn(func(resp *api.WatchResponse) error {
+		if resp.Type == api.EventType_RESOLVED {
+			return nil
+		}
+		sending <- struct{}{}
+		<-ctx.Done()
+		return ctx.Err()
+	}).AnyTimes()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- s.Watch(&api.WatchRequest{Version: "v1", Resource: "pod", Namespace: "default", BufferSize: 1, BackpressurePolicy: api.BackpressurePolicy_BLOCK, BlockTimeoutMs: 20000}, stream)
+	}()
+
+	select {
+	case <-sending:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Timed out waiting for the first send")
+	}
+	// Fill the queue, then block the handler on the next pod
+	for _, name := range []string{"redis", "busybox"} {
+		if _, err := s.dynamicClient.Resource(podsGVR).Namespace("default").Create(context.Background(), newTestPod("default", name), metav1.CreateOptions{}); err != nil {
+			t.Fatalf("Failed to create pod: %v", err)
+		}
+	}
+	waitForCondition(t, func() bool {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		for _, sess := range s.sessions {
+			sess.events.mu.Lock()
+			defer sess.events.mu.Unlock()
+			return sess.events.size == 1
+		}
+		return false
+	})
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Expected the stream to end without waiting for the block timeout")
+	}
+	if s.sessionCount() != 0 {
+		t.Errorf("Expected no live sessions, got %d", s.sessionCount())
+	}
+}
+
+// startTestWatchMany is startTestWatch for WatchMany.
+func startTestWatchMany(ctrl *gomock.Controller, s *server, req *api.WatchManyRequest) (<-chan *api.WatchResponse, context.CancelFunc, <-chan error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	events := make(chan *api.WatchResponse, 100)
+
+	stream := mocks.NewMockWatchService_WatchManyServer(ctrl)
+	stream.EXPECT().Context().Return(ctx).AnyTimes()
+	stream.EXPECT().Send(gomock.Any()).DoAndReturn(func(event *api.WatchResponse) error {
+		events <- event
+		return nil
+	}).AnyTimes()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- s.WatchMany(req, stream)
+	}()
+	return events, cancel, done
+}
+
+func TestWatchMany(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := newTestWatchServer(ctrl, newTestPod("default", "nginx"), newTestPod("other", "redis"))
+	listAtResourceVersion(s.dynamicClient.(*fake.FakeDynamicClient), podsGVR, "10")
+	events, cancel, done := startTestWatchMany(ctrl, s, &api.WatchManyRequest{Targets: []*api.WatchRequest{
+		{Version: "v1", Resource: "pod", Namespace: "default", Id: "default-pods"},
+		{Version: "v1", Resource: "pod", Namespace: "other"},
+		{Version: "v1", Resource: "pod", Namespace: "default", ResourceVersion: "5"},
+	}})
+
+	received := make(map[string][]string)
+	for i := 0; i < 7; i++ {
+		event := waitForEvent(t, events)
+		received[event.SubscriptionId] = append(received[event.SubscriptionId], event.EventType+"/"+event.Name)
+	}
+	expected := map[string][]string{
+		"default-pods": {"RESOLVED/", "ADD/nginx", "SYNCED/"},
+		"1":            {"RESOLVED/", "ADD/redis", "SYNCED/"},
+		"2":            {"ERROR/"},
+	}
+	if !reflect.DeepEqual(expected, received) {
+		t.Errorf("expected: %v, got: %v", expected, received)
+	}
+	if s.informers.len() != 2 {
+		t.Errorf("Expected targets to share informers, got %d", s.informers.len())
+	}
+
+	cancel()
+	<-done
+	waitForCondition(t, func() bool { return s.informers.len() == 0 && s.sessionCount() == 0 })
+}
+
+func TestWatchManyInvalidTargets(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := newTestWatchServer(ctrl)
+	tests := []struct {
+		name string
+		req  *api.WatchManyRequest
+	}{
+		{name: "No targets", req: &api.WatchManyRequest{}},
+		{name: "Duplicate ids", req: &api.WatchManyRequest{Targets: []*api.WatchRequest{
+			{Version: "v1", Resource: "pod", Id: "pods"},
+			{Version: "v1", Resource: "pod", Namespace: "default", Id: "pods"},
+		}}},
+		{name: "Invalid selector", req: &api.WatchManyRequest{Targets: []*api.WatchRequest{
+			{Version: "v1", Resource: "pod"},
+			{Version: "v1", Resource: "pod", LabelSelector: "app in (web"},
+		}}},
+		{name: "Invalid resource version", req: &api.WatchManyRequest{Targets: []*api.WatchRequest{
+			{Version: "v1", Resource: "pod", ResourceVersion: "not-a-version"},
+		}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := s.WatchMany(tt.req, mocks.NewMockWatchService_WatchManyServer(ctrl)); err == nil {
+				t.Errorf("Expected an error")
+			}
+		})
+	}
+}
+
+func TestFormatRequestStatus(t *testing.T) {
+	gvr := "/v1, Resource=pods"
+	tests := []struct {
+		name        string
+		req         *api.WatchRequest
+		resolveErr  error
+		code        codes.Code
+		field       string
+		resourceGVR string
+	}{
+		{
+			name:  "Missing resource",
+			req:   &api.WatchRequest{Version: "v1"},
+			code:  codes.InvalidArgument,
+			field: "resource",
+		},
+		{
+			name:  "Invalid label selector",
+			req:   &api.WatchRequest{Version: "v1", Resource: "pods", LabelSelector: "app in (web"},
+			code:  codes.InvalidArgument,
+			field: "label_selector",
+		},
+		{
+			name:  "Invalid field selector",
+			req:   &api.WatchRequest{Version: "v1", Resource: "pods", FieldSelector: "status.phase"},
+			code:  codes.InvalidArgument,
+			field: "field_selector",
+		},
+		{
+			name:  "Invalid filter",
+			req:   &api.WatchRequest{Version: "v1", Resource: "pods", Filter: "object.status.phase =="},
+			code:  codes.InvalidArgument,
+			field: "filter",
+		},
+		{
+			name:  "Filter not a bool",
+			req:   &api.WatchRequest{Version: "v1", Resource: "pods", Filter: "eventType"},
+			code:  codes.InvalidArgument,
+			field: "filter",
+		},
+		{
+			name:  "Empty field path",
+			req:   &api.WatchRequest{Version: "v1", Resource: "pods", Fields: []string{"status", "metadata..name"}},
+			code:  codes.InvalidArgument,
+			field: "fields[1]",
+		},
+		{
+			name:  "Empty ignored path",
+			req:   &api.WatchRequest{Version: "v1", Resource: "pods", IgnorePaths: []string{"status."}},
+			code:  codes.InvalidArgument,
+			field: "ignore_paths[0]",
+		},
+		{
+			name:  "Empty namespace",
+			req:   &api.WatchRequest{Version: "v1", Resource: "pods", Namespaces: []string{"default", ""}},
+			code:  codes.InvalidArgument,
+			field: "namespaces[1]",
+		},
+		{
+			name:        "Unknown resource",
+			req:         &api.WatchRequest{Version: "v1", Resource: "pods"},
+			resolveErr:  fmt.Errorf("%w: pods", errResourceNotFound),
+			code:        codes.NotFound,
+			resourceGVR: gvr,
+		},
+		{
+			name:        "Unknown group version",
+			req:         &api.WatchRequest{Version: "v1", Resource: "pods"},
+			resolveErr:  apierrors.NewNotFound(schema.GroupResource{}, "v1"),
+			code:        codes.NotFound,
+			resourceGVR: gvr,
+		},
+		{
+			name:        "Forbidden",
+			req:         &api.WatchRequest{Version: "v1", Resource: "pods"},
+			resolveErr:  apierrors.NewForbidden(schema.GroupResource{}, "v1", errors.New("no access")),
+			code:        codes.PermissionDenied,
+			resourceGVR: gvr,
+		},
+		{
+			name:        "Unauthorized",
+			req:         &api.WatchRequest{Version: "v1", Resource: "pods"},
+			resolveErr:  apierrors.NewUnauthorized("bad token"),
+			code:        codes.PermissionDenied,
+			resourceGVR: gvr,
+		},
+		{
+			name:        "Ambiguous resource",
+			req:         &api.WatchRequest{Version: "v1", Resource: "pods"},
+			resolveErr:  &meta.AmbiguousResourceError{PartialResource: schema.GroupVersionResource{Version: "v1", Resource: "pods"}},
+			code:        codes.InvalidArgument,
+			resourceGVR: gvr,
+		},
+		{
+			name:        "API server unreachable",
+			req:         &api.WatchRequest{Version: "v1", Resource: "pods"},
+			resolveErr:  errors.New("connection refused"),
+			code:        codes.Unavailable,
+			resourceGVR: gvr,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			s := &server{resolver: resolverFunc(func(gvr schema.GroupVersionResource) ([]schema.GroupVersionResource, error) {
+				return []schema.GroupVersionResource{gvr}, tc.resolveErr
+			})}
+			_, err := s.formatRequest(tc.req)
+			st := status.Convert(err)
+			if st.Code() != tc.code {
+				t.Fatalf("Expected %s, got %v", tc.code, err)
+			}
+			assertStatusDetails(t, st, tc.field, tc.resourceGVR)
+		})
+	}
+}
+
+func assertStatusDetails(t *testing.T, st *status.Status, field, resourceGVR string) {
+	t.Helper()
+	if len(st.Details()) != 1 {
+		t.Fatalf("Expected one detail, got %v", st.Details())
+	}
+	switch detail := st.Details()[0].(type) {
+	case *errdetails.BadRequest:
+		if violation := detail.FieldViolations[0]; violation.Field != field {
+			t.Errorf("Expected a violation of %s, got %s", field, violation.Field)
+		}
+	case *errdetails.ResourceInfo:
+		if detail.ResourceName != resourceGVR {
+			t.Errorf("Expected resource %s, got %s", resourceGVR, detail.ResourceName)
+		}
+	default:
+		t.Errorf("Unexpected detail %T", detail)
+	}
+}
+
+func TestWatchInvalidRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := newTestWatchServer(ctrl)
+	tests := []struct {
+		name  string
+		watch func() error
+		field string
+	}{
+		{
+			name: "Watch",
+			watch: func() error {
+				return s.Watch(&api.WatchRequest{Version: "v1", Resource: "pod", LabelSelector: "app in (web"}, mocks.NewMockWatchService_WatchServer(ctrl))
+			},
+			field: "label_selector",
+		},
+		{
+			name: "Watch buffer size",
+			watch: func() error {
+				return s.Watch(&api.WatchRequest{Version: "v1", Resource: "pod", BufferSize: maxBufferSize + 1}, mocks.NewMockWatchService_WatchServer(ctrl))
+			},
+			field: "buffer_size",
+		},
+		{
+			name: "Watch block timeout",
+			watch: func() error {
+				return s.Watch(&api.WatchRequest{Version: "v1", Resource: "pod", BackpressurePolicy: api.BackpressurePolicy_BLOCK, BlockTimeoutMs: 60000}, mocks.NewMockWatchService_WatchServer(ctrl))
+			},
+			field: "block_timeout_ms",
+		},
+		{
+			name: "Watch resource version",
+			watch: func() error {
+				return s.Watch(&api.WatchRequest{Version: "v1", Resource: "pod", ResourceVersion: "not-a-version"}, mocks.NewMockWatchService_WatchServer(ctrl))
+			},
+			field: "resource_version",
+		},
+		{
+			name: "Watch resync period",
+			watch: func() error {
+				return s.Watch(&api.WatchRequest{Version: "v1", Resource: "pod", IncludeResyncs: true, ResyncPeriodSeconds: 60}, mocks.NewMockWatchService_WatchServer(ctrl))
+			},
+			field: "resync_period_seconds",
+		},
+		{
+			name: "WatchMany target",
+			watch: func() error {
+				return s.WatchMany(&api.WatchManyRequest{Targets: []*api.WatchRequest{
+					{Version: "v1", Resource: "pod"},
+					{Version: "v1", Resource: "pod", FieldSelector: "status.phase"},
+				}}, mocks.NewMockWatchService_WatchManyServer(ctrl))
+			},
+			field: "targets[1].field_selector",
+		},
+		{
+			name: "WatchMany duplicate id",
+			watch: func() error {
+				return s.WatchMany(&api.WatchManyRequest{Targets: []*api.WatchRequest{
+					{Version: "v1", Resource: "pod", Id: "pods"},
+					{Version: "v1", Resource: "pod", Id: "pods"},
+				}}, mocks.NewMockWatchService_WatchManyServer(ctrl))
+			},
+			field: "targets[1].id",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			st := status.Convert(tc.watch())
+			if st.Code() != codes.InvalidArgument {
+				t.Fatalf("Expected InvalidArgument, got %v", st.Err())
+			}
+			assertStatusDetails(t, st, tc.field, "")
+		})
+	}
+}
+
+func TestValidateResyncs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	tests := []struct {
+		name         string
+		serverPeriod time.Duration
+		req          *api.WatchRequest
+		field        string
+	}{
+		{name: "No resyncs", serverPeriod: 0, req: &api.WatchRequest{ResyncPeriodSeconds: 1}},
+		{name: "Server period", serverPeriod: time.Minute, req: &api.WatchRequest{IncludeResyncs: true}},
+		{name: "Longer period", serverPeriod: time.Minute, req: &api.WatchRequest{IncludeResyncs: true, ResyncPeriodSeconds: 90}},
+		{name: "Same period", serverPeriod: time.Minute, req: &api.WatchRequest{IncludeResyncs: true, ResyncPeriodSeconds: 60}},
+		{name: "Shorter period", serverPeriod: time.Minute, req: &api.WatchRequest{IncludeResyncs: true, ResyncPeriodSeconds: 30}, field: "resync_period_seconds"},
+		{name: "Resyncs disabled", serverPeriod: 0, req: &api.WatchRequest{IncludeResyncs: true}, field: "include_resyncs"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			s := newTestWatchServer(ctrl)
+			s.informers.resyncPeriod = tc.serverPeriod
+			err := s.validateResyncs(tc.req)
+			if tc.field == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+			st := status.Convert(err)
+			if st.Code() != codes.InvalidArgument {
+				t.Fatalf("Expected InvalidArgument, got %v", err)
+			}
+			assertStatusDetails(t, st, tc.field, "")
+		})
+	}
+}
+
+func TestWatchUninitializedClient(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := newTestWatchServer(ctrl)
+	s.dynamicClient = nil
+	err := s.Watch(&api.WatchRequest{Version: "v1", Resource: "pod"}, mocks.NewMockWatchService_WatchServer(ctrl))
+	if status.Code(err) != codes.Unavailable {
+		t.Errorf("Expected Unavailable, got %v", err)
+	}
+}
@@ -0,0 +1,109 @@
+package server
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cmwylie19/watch-informer/api"
+)
+
+// session is the server side of a single Watch or WatchMany stream. The
+// event handlers of its subscriptions push into its queue and the stream
+// drains it.
+type session struct {
+	id     uint64
+	events *eventQueue
+}
+
+// queueOptions are the request fields that configure a session's queue.
+type queueOptions interface {
+	GetBackpressurePolicy() api.BackpressurePolicy
+	GetBufferSize() uint32
+	GetBlockTimeoutMs() uint32
+}
+
+// validateQueueOptions rejects options that would make a session's queue
+// unreasonably large or let a slow client hold up its informers for long.
+func validateQueueOptions(opts queueOptions) error {
+	if opts.GetBufferSize() > maxBufferSize {
+		return invalidArgument("buffer_size", fmt.Sprintf("buffer size %d exceeds the maximum of %d", opts.GetBufferSize(), maxBufferSize))
+	}
+	if blockTimeout := time.Duration(opts.GetBlockTimeoutMs()) * time.Millisecond; blockTimeout > maxBlockTimeout {
+		return invalidArgument("block_timeout_ms", fmt.Sprintf("block timeout %v exceeds the maximum of %v", blockTimeout, maxBlockTimeout))
+	}
+	return nil
+}
+
+// newSession creates a session whose queue follows the buffering and
+// backpressure options of opts.
+func newSession(id uint64, opts queueOptions) *session {
+	bufferSize := defaultBufferSize
+	if opts.GetBufferSize() > 0 {
+		bufferSize = int(opts.GetBufferSize())
+	}
+	blockTimeout := defaultBlockTimeout
+	if opts.GetBlockTimeoutMs() > 0 {
+		blockTimeout = time.Duration(opts.GetBlockTimeoutMs()) * time.Millisecond
+	}
+
+	return &session{
+		id:     id,
+		events: newEventQueue(bufferSize, opts.GetBackpressurePolicy(), blockTimeout),
+	}
+}
+
+// push queues an event for the client. It reports false when the event was
+// dropped by the backpressure policy or the session has been closed.
+func (ss *session) push(event *queuedEvent) bool {
+	return ss.events.push(event)
+}
+
+// close stops accepting events and returns anything still queued. It is
+// safe to call more than once.
+func (ss *session) close() []*queuedEvent {
+	return ss.events.close()
+}
+
+// openSession registers a new session for a stream.
+func (s *server) openSession(opts queueOptions) *session {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.nextSessionID++
+	sess := newSession(s.nextSessionID, opts)
+	s.sessions[sess.id] = sess
+	return sess
+}
+
+// closeSession closes the session and deregisters it from the server. It
+// returns the events that were queued but never sent.
+func (s *server) closeSession(sess *session) []*queuedEvent {
+	unsent := sess.close()
+
+	s.mu.Lock()
+	delete(s.sessions, sess.id)
+	s.mu.Unlock()
+	return unsent
+}
+
+// endSession closes sess once its stream is done. With a delivery, the events
+// still queued are handed to detach, so a client reconnecting with the
+// delivery id gets them too.
+func (s *server) endSession(sess *session, detach func([]*api.WatchResponse)) {
+	unsent := s.closeSession(sess)
+	if detach == nil {
+		return
+	}
+	resps := make([]*api.WatchResponse, len(unsent))
+	for i, event := range unsent {
+		resps[i] = s.toResponse(event)
+	}
+	detach(resps)
+}
+
+// sessionCount returns the number of live sessions.
+func (s *server) sessionCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.sessions)
+}
@@ -0,0 +1,249 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/cmwylie19/watch-informer/api"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// WatchStream streams the events of subscriptions that the client adds and
+// removes at runtime. Only a failure to receive or send ends the stream;
+// problems with individual messages are reported as ERROR events.
+func (s *server) WatchStream(srv api.WatchService_WatchStreamServer) error {
+	defer func() {
+		if r := recover(); r != nil {
+			s.Logger.Error(fmt.Sprint("Recovered in WatchStream", r))
+		}
+	}()
+
+	first, err := srv.Recv()
+	if errors.Is(err, io.EOF) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	opts := &api.StreamOptions{}
+	if first.GetOptions() != nil {
+		opts = first.GetOptions()
+		first = nil
+	}
+	if err := validateQueueOptions(opts); err != nil {
+		return withFieldPrefix(err, "options.")
+	}
+	if s.dynamicClient == nil {
+		return errClientNotInitialized
+	}
+
+	ctx, cancel := context.WithCancelCause(srv.Context())
+	defer cancel(nil)
+
+	d := newDelivery("")
+	var detach func([]*api.WatchResponse)
+	if opts.DeliveryId != "" {
+		d, detach = s.deliveries.attach(opts.DeliveryId, cancel)
+	}
+
+	sess := s.openSession(opts)
+	defer s.endSession(sess, detach)
+
+	ws := &watchStream{
+		server:        s,
+		ctx:           ctx,
+		sess:          sess,
+		delivery:      d,
+		subscriptions: make(map[string]*streamSubscription),
+	}
+	defer ws.close()
+
+	go func() {
+		if first != nil {
+			ws.handle(first)
+		}
+		for {
+			msg, err := srv.Recv()
+			if errors.Is(err, io.EOF) {
+				// The client is done sending but still wants its events
+				return
+			}
+			if err != nil {
+				cancel(err)
+				return
+			}
+			ws.handle(msg)
+		}
+	}()
+
+	err = s.sendEvents(ctx, srv, sess, d)
+	if cause := context.Cause(ctx); cause != nil && srv.Context().Err() == nil {
+		return cause
+	}
+	return err
+}
+
+// watchStream tracks the subscriptions of a WatchStream stream.
+type watchStream struct {
+	server        *server
+	ctx           context.Context
+	sess          *session
+	delivery      *delivery
+	mu            sync.Mutex
+	subscriptions map[string]*streamSubscription
+	closed        bool
+	// setUps tracks the subscriptions being set up in the background.
+	setUps sync.WaitGroup
+}
+
+// streamSubscription is a subscription of the stream. Its sub is nil while
+// it is set up, which can take as long as listing its informers.
+type streamSubscription struct {
+	sub    *subscription
+	cancel context.CancelFunc
+}
+
+// handle applies a message from the client.
+func (ws *watchStream) handle(msg *api.WatchStreamRequest) {
+	switch {
+	case msg.GetSubscribe() != nil:
+		ws.subscribe(msg.GetSubscribe())
+	case msg.GetUnsubscribe() != nil:
+		ws.unsubscribe(msg.GetUnsubscribe().Id)
+	case msg.GetAck() != nil:
+		ws.delivery.ack(msg.GetAck().Sequence)
+	case msg.GetFlowControl() != nil:
+		ws.delivery.update(msg.GetFlowControl())
+	case msg.GetOptions() != nil:
+		ws.reject("", "options are only allowed as the first message")
+	default:
+		ws.reject("", "empty message")
+	}
+}
+
+// subscribe adds a subscription. It is set up in the background, so acks,
+// flow control and other messages are handled while its informers list.
+func (ws *watchStream) subscribe(req *api.WatchRequest) {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	if ws.closed {
+		return
+	}
+	if req.Id == "" {
+		ws.reject("", "subscription id is required")
+		return
+	}
+	if _, ok := ws.subscriptions[req.Id]; ok {
+		ws.reject(req.Id, fmt.Sprintf("duplicate subscription id %q", req.Id))
+		return
+	}
+
+	ctx, cancel := context.WithCancel(ws.ctx)
+	ss := &streamSubscription{cancel: cancel}
+	ws.subscriptions[req.Id] = ss
+	ws.setUps.Add(1)
+	go func() {
+		defer ws.setUps.Done()
+		ws.setUp(ctx, ss, req)
+	}()
+}
+
+// setUp subscribes req for ss and reports a failure as an ERROR event. A
+// subscription removed while it was set up is stopped right away.
+func (ws *watchStream) setUp(ctx context.Context, ss *streamSubscription, req *api.WatchRequest) {
+	id := req.Id
+	req, err := ws.server.formatRequest(req)
+	var sub *subscription
+	if err == nil {
+		sub, err = ws.server.subscribe(ctx, ws.sess, req)
+	}
+
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	if ws.subscriptions[id] != ss {
+		// Unsubscribed, or the stream closed, while it was set up
+		if sub != nil {
+			ws.server.unsubscribe(sub)
+		}
+		ss.cancel()
+		if !ws.closed {
+			ws.pushUnsubscribed(id)
+		}
+		return
+	}
+
+	switch {
+	case err == nil:
+		ss.sub = sub
+		return
+	case errors.Is(err, errResourceVersionExpired):
+		ws.server.Logger.Info(fmt.Sprintf("Cannot resume watch for %s from resourceVersion %s: %v", formatSessionID(req), req.ResourceVersion, err))
+		ws.sendError(id, apierrors.NewResourceExpired(err.Error()))
+	default:
+		ws.server.Logger.Error(fmt.Sprintf("Failed to subscribe %s: %v", id, err))
+		ws.sendError(id, apiStatusFromError(err))
+	}
+	delete(ws.subscriptions, id)
+	ss.cancel()
+}
+
+// unsubscribe removes a subscription. Its UNSUBSCRIBED event follows its
+// last queued event.
+func (ws *watchStream) unsubscribe(id string) {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	ss, ok := ws.subscriptions[id]
+	if !ok {
+		ws.reject(id, fmt.Sprintf("unknown subscription id %q", id))
+		return
+	}
+	delete(ws.subscriptions, id)
+	ss.cancel()
+	if ss.sub == nil {
+		// Setting up stops early and sends the UNSUBSCRIBED event
+		return
+	}
+	ws.server.unsubscribe(ss.sub)
+	ws.pushUnsubscribed(id)
+}
+
+func (ws *watchStream) pushUnsubscribed(id string) {
+	resp := newUnsubscribedResponse()
+	resp.SubscriptionId = id
+	ws.sess.push(&queuedEvent{response: resp})
+}
+
+// close removes every subscription and ignores any later subscribe. It
+// returns once the subscriptions being set up have stopped.
+func (ws *watchStream) close() {
+	ws.mu.Lock()
+	ws.closed = true
+	for id, ss := range ws.subscriptions {
+		delete(ws.subscriptions, id)
+		ss.cancel()
+		if ss.sub != nil {
+			ws.server.unsubscribe(ss.sub)
+		}
+	}
+	ws.mu.Unlock()
+
+	ws.setUps.Wait()
+}
+
+// reject reports an invalid message as a 400 Bad Request ERROR event.
+func (ws *watchStream) reject(id, message string) {
+	ws.server.Logger.Error(fmt.Sprintf("Rejected stream message: %s", message))
+	ws.sendError(id, apierrors.NewBadRequest(message))
+}
+
+func (ws *watchStream) sendError(id string, err apierrors.APIStatus) {
+	resp := newErrorResponse(err)
+	resp.SubscriptionId = id
+	ws.sess.push(&queuedEvent{response: resp})
+}
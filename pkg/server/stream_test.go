@@ -0,0 +1,232 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/cmwylie19/watch-informer/api"
+	"github.com/cmwylie19/watch-informer/mocks"
+)
+
+// startTestWatchStream runs WatchStream in the background. Messages written
+// to the returned channel are received by the server, and closing it ends
+// the client side of the stream.
+func startTestWatchStream(ctrl *gomock.Controller, s *server) (chan<- *api.WatchStreamRequest, <-chan *api.WatchResponse, context.CancelFunc, <-chan error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	requests := make(chan *api.WatchStreamRequest, 10)
+	events := make(chan *api.WatchResponse, 100)
+
+	stream := mocks.NewMockWatchService_WatchStreamServer(ctrl)
+	stream.EXPECT().Context().Return(ctx).AnyTimes()
+	stream.EXPECT().Recv().DoAndReturn(func() (*api.WatchStreamRequest, error) {
+		select {
+		case msg, ok := <-requests:
+			if !ok {
+				return nil, io.EOF
+			}
+			return msg, nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}).AnyTimes()
+	stream.EXPECT().Send(gomock.Any()).DoAndReturn(func(event *api.WatchResponse) error {
+		events <- event
+		return nil
+	}).AnyTimes()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- s.WatchStream(stream)
+	}()
+	return requests, events, cancel, done
+}
+
+func subscribeRequest(req *api.WatchRequest) *api.WatchStreamRequest {
+	return &api.WatchStreamRequest{Request: &api.WatchStreamRequest_Subscribe{Subscribe: req}}
+}
+
+func TestWatchStreamSubscribe(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := newTestWatchServer(ctrl, newTestPod("default", "nginx"))
+	requests, events, cancel, done := startTestWatchStream(ctrl, s)
+
+	requests <- subscribeRequest(&api.WatchRequest{Version: "v1", Resource: "pod", Namespace: "default", Id: "pods"})
+	waitForEventType(t, events, api.EventType_RESOLVED)
+	event := waitForEventType(t, events, api.EventType_ADD)
+	if event.SubscriptionId != "pods" || event.Sequence != 2 {
+		t.Errorf("Expected event 2 of subscription pods, got %d of %q", event.Sequence, event.SubscriptionId)
+	}
+	if event := waitForEventType(t, events, api.EventType_SYNCED); event.Sequence != 3 {
+		t.Errorf("Expected sequence 3, got %d", event.Sequence)
+	}
+
+	requests <- &api.WatchStreamRequest{Request: &api.WatchStreamRequest_Unsubscribe{Unsubscribe: &api.Unsubscribe{Id: "pods"}}}
+	if event := waitForEventType(t, events, api.EventType_UNSUBSCRIBED); event.SubscriptionId != "pods" {
+		t.Errorf("Expected subscription pods, got %q", event.SubscriptionId)
+	}
+	if s.informers.len() != 0 {
+		t.Errorf("Expected the informer to be released, got %d", s.informers.len())
+	}
+
+	// The stream outlives its subscriptions and the client's send side
+	requests <- subscribeRequest(&api.WatchRequest{Version: "v1", Resource: "pod", Namespace: "default", Id: "pods"})
+	close(requests)
+	waitForEventType(t, events, api.EventType_RESOLVED)
+	waitForEventType(t, events, api.EventType_ADD)
+	waitForEventType(t, events, api.EventType_SYNCED)
+
+	cancel()
+	<-done
+	waitForCondition(t, func() bool { return s.informers.len() == 0 && s.sessionCount() == 0 })
+}
+
+func TestWatchStreamRejectsInvalidMessages(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := newTestWatchServer(ctrl)
+	requests, events, cancel, done := startTestWatchStream(ctrl, s)
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	tests := []struct {
+		name string
+		msg  *api.WatchStreamRequest
+		id   string
+	}{
+		{name: "Missing id", msg: subscribeRequest(&api.WatchRequest{Version: "v1", Resource: "pod"})},
+		{name: "Invalid selector", msg: subscribeRequest(&api.WatchRequest{Version: "v1", Resource: "pod", Id: "pods", LabelSelector: "app in (web"}), id: "pods"},
+		{name: "Unknown subscription", msg: &api.WatchStreamRequest{Request: &api.WatchStreamRequest_Unsubscribe{Unsubscribe: &api.Unsubscribe{Id: "nodes"}}}, id: "nodes"},
+		{name: "Late options", msg: &api.WatchStreamRequest{Request: &api.WatchStreamRequest_Options{Options: &api.StreamOptions{}}}},
+		{name: "Empty message", msg: &api.WatchStreamRequest{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests <- tt.msg
+			event := waitForEventType(t, events, api.EventType_ERROR)
+			if event.SubscriptionId != tt.id {
+				t.Errorf("Expected subscription %q, got %q", tt.id, event.SubscriptionId)
+			}
+			var status metav1.Status
+			if err := json.Unmarshal(event.Object, &status); err != nil {
+				t.Fatalf("Expected a Status object: %v", err)
+			}
+			if status.Code != http.StatusBadRequest {
+				t.Errorf("Expected 400 Bad Request, got %d", status.Code)
+			}
+		})
+	}
+}
+
+func TestWatchStreamFlowControl(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := newTestWatchServer(ctrl, newTestPod("default", "nginx"), newTestPod("default", "redis"))
+	requests, events, cancel, done := startTestWatchStream(ctrl, s)
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	requests <- &api.WatchStreamRequest{Request: &api.WatchStreamRequest_FlowControl{FlowControl: &api.FlowControl{MaxInFlight: 1}}}
+	requests <- subscribeRequest(&api.WatchRequest{Version: "v1", Resource: "pod", Namespace: "default", Id: "pods"})
+
+	first := waitForEventType(t, events, api.EventType_RESOLVED)
+	select {
+	case event := <-events:
+		t.Fatalf("Expected no event before the ack, got %s", event.EventType)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	requests <- &api.WatchStreamRequest{Request: &api.WatchStreamRequest_Ack{Ack: &api.Ack{Sequence: first.Sequence}}}
+	if event := waitForEventType(t, events, api.EventType_ADD); event.Sequence != 2 {
+		t.Errorf("Expected sequence 2, got %d", event.Sequence)
+	}
+
+	requests <- &api.WatchStreamRequest{Request: &api.WatchStreamRequest_FlowControl{FlowControl: &api.FlowControl{Pause: true}}}
+	requests <- &api.WatchStreamRequest{Request: &api.WatchStreamRequest_Ack{Ack: &api.Ack{Sequence: 2}}}
+	select {
+	case event := <-events:
+		t.Fatalf("Expected no event while paused, got %s", event.EventType)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	requests <- &api.WatchStreamRequest{Request: &api.WatchStreamRequest_FlowControl{FlowControl: &api.FlowControl{}}}
+	if event := waitForEventType(t, events, api.EventType_ADD); event.Sequence != 3 {
+		t.Errorf("Expected sequence 3, got %d", event.Sequence)
+	}
+	requests <- &api.WatchStreamRequest{Request: &api.WatchStreamRequest_Ack{Ack: &api.Ack{Sequence: 3}}}
+	waitForEventType(t, events, api.EventType_SYNCED)
+}
+
+func TestWatchStreamSubscribesInBackground(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := newTestWatchServer(ctrl, newTestPod("default", "nginx"))
+	// Resolving widgets blocks until the test lets it go
+	resolving := make(chan struct{})
+	resolver := s.resolver
+	s.resolver = resolverFunc(func(gvr schema.GroupVersionResource) ([]schema.GroupVersionResource, error) {
+		if gvr.Resource == "widgets" {
+			<-resolving
+		}
+		return resolver.resourcesFor(gvr)
+	})
+	requests, events, cancel, done := startTestWatchStream(ctrl, s)
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	// Every event has to be acked while widgets are still resolving
+	next := func() *api.WatchResponse {
+		event := waitForEvent(t, events)
+		requests <- &api.WatchStreamRequest{Request: &api.WatchStreamRequest_Ack{Ack: &api.Ack{Sequence: event.Sequence}}}
+		return event
+	}
+	requests <- &api.WatchStreamRequest{Request: &api.WatchStreamRequest_FlowControl{FlowControl: &api.FlowControl{MaxInFlight: 1}}}
+	requests <- subscribeRequest(&api.WatchRequest{Version: "v1", Resource: "widgets", Namespace: "default", Id: "widgets"})
+	requests <- subscribeRequest(&api.WatchRequest{Version: "v1", Resource: "pod", Namespace: "default", Id: "pods"})
+	for _, eventType := range []api.EventType{api.EventType_RESOLVED, api.EventType_ADD, api.EventType_SYNCED} {
+		if event := next(); event.SubscriptionId != "pods" || event.Type != eventType {
+			t.Fatalf("Expected %s of pods, got %s of %q", eventType, event.Type, event.SubscriptionId)
+		}
+	}
+
+	requests <- &api.WatchStreamRequest{Request: &api.WatchStreamRequest_Unsubscribe{Unsubscribe: &api.Unsubscribe{Id: "widgets"}}}
+	close(resolving)
+	// widgets may finish subscribing before the unsubscribe is handled
+	for event := next(); event.Type != api.EventType_UNSUBSCRIBED; event = next() {
+		if event.SubscriptionId != "widgets" {
+			t.Fatalf("Expected only widgets events, got %s of %q", event.Type, event.SubscriptionId)
+		}
+	}
+}
+
+func TestWatchStreamOptions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := newTestWatchServer(ctrl)
+	requests, _, cancel, done := startTestWatchStream(ctrl, s)
+	defer cancel()
+
+	requests <- &api.WatchStreamRequest{Request: &api.WatchStreamRequest_Options{Options: &api.StreamOptions{BufferSize: maxBufferSize + 1}}}
+	if err := <-done; err == nil {
+		t.Errorf("Expected an error for an oversized buffer")
+	}
+}
@@ -0,0 +1,537 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/cmwylie19/watch-informer/api"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+)
+
+var namespacesGVR = schema.GroupVersionResource{Version: "v1", Resource: "namespaces"}
+
+// subscription is one watch target of a session. It attaches an event
+// handler to the shared informer of every resource and namespace it covers
+// and queues their events on the session tagged with its id.
+type subscription struct {
+	id         string
+	key        string
+	req        *api.WatchRequest
+	filter     *eventFilter
+	projection *projection
+	ignored    ignoredChanges
+	sess       *session
+	ctx        context.Context
+	cancel     context.CancelFunc
+	mu         sync.RWMutex
+	stopped    bool
+
+	// attachMu guards the resources and the attachments of each namespace,
+	// which change as namespaces start or stop matching the namespace
+	// selector and as resources come and go.
+	attachMu    sync.Mutex
+	resources   []schema.GroupVersionResource
+	attachments map[string][]*attachment
+	namespaces  *attachment
+	apis        []*attachment
+}
+
+// attachment is an event handler attached to a shared informer.
+type attachment struct {
+	key          string
+	gvr          schema.GroupVersionResource
+	kind         schema.GroupVersionKind
+	shared       *sharedInformer
+	registration *handlerRegistration
+}
+
+// push queues an event on the session unless the subscription has been
+// stopped. It reports false when the event was dropped.
+func (sub *subscription) push(event *queuedEvent) bool {
+	sub.mu.RLock()
+	defer sub.mu.RUnlock()
+	if sub.stopped {
+		return true
+	}
+	event.subscription = sub
+	return sub.sess.push(event)
+}
+
+// stop ensures no further events of the subscription are queued. Canceling
+// first wakes a push blocked on a full queue, which holds mu.
+func (sub *subscription) stop() {
+	sub.cancel()
+	sub.mu.Lock()
+	sub.stopped = true
+	sub.mu.Unlock()
+}
+
+func (sub *subscription) isStopped() bool {
+	sub.mu.RLock()
+	defer sub.mu.RUnlock()
+	return sub.stopped
+}
+
+// subscribe attaches a subscription for req to sess and starts queueing its
+// events. Every informer behind it is released again, and its queued events
+// dropped, if the subscription cannot be attached.
+func (s *server) subscribe(ctx context.Context, sess *session, req *api.WatchRequest) (*subscription, error) {
+	key := formatSessionID(req)
+	s.Logger.Info(fmt.Sprintf("Starting watch for %s", key))
+
+	var filter *eventFilter
+	if req.Filter != "" {
+		var err error
+		if filter, err = compileFilter(req.Filter); err != nil {
+			return nil, err
+		}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	sub := &subscription{
+		id:          req.Id,
+		key:         key,
+		req:         req,
+		filter:      filter,
+		projection:  newProjection(req),
+		ignored:     newIgnoredChanges(req),
+		sess:        sess,
+		ctx:         ctx,
+		cancel:      cancel,
+		attachments: make(map[string][]*attachment),
+	}
+	initial, err := s.attachInitial(sub)
+	if err != nil {
+		sub.stop()
+		s.detachAll(sub)
+		sess.events.discard(sub)
+		return nil, err
+	}
+
+	if len(initial) > 0 {
+		go s.sendSynced(ctx, sub, initial)
+	}
+	return sub, nil
+}
+
+// unsubscribe stops the events of sub and releases its informers.
+func (s *server) unsubscribe(sub *subscription) {
+	sub.stop()
+	s.detachAll(sub)
+	s.Logger.Info(fmt.Sprintf("Stopping watch for %s", sub.key))
+}
+
+// attachInitial resolves the resources of sub and attaches it to the
+// namespaces it covers when subscribing, which are the only ones resumed
+// from the requested resourceVersion. A subscription waiting for its
+// resource covers the namespaces without attaching anything yet.
+func (s *server) attachInitial(sub *subscription) ([]*attachment, error) {
+	req := sub.req
+	namespaces := req.Namespaces
+	if len(namespaces) == 0 && req.NamespaceLabelSelector == "" {
+		namespaces = []string{req.Namespace}
+	}
+
+	sub.attachMu.Lock()
+	defer sub.attachMu.Unlock()
+
+	// Follow CRDs and APIServices before resolving, so no change is missed
+	if req.WaitForResource {
+		if err := s.watchAPIs(sub); err != nil {
+			return nil, err
+		}
+	}
+	resources, err := s.resolve(req)
+	switch {
+	case waitsForResource(req, err):
+		s.Logger.Info(fmt.Sprintf("Waiting for the resource of %s: %v", sub.key, err))
+		sub.push(&queuedEvent{response: newStatusResponse(waitingMessage(req))})
+	case err != nil:
+		return nil, err
+	default:
+		sub.resources = resources
+		// Queued before any informer event, so it comes first
+		sub.push(&queuedEvent{response: newResolvedResponse(resources)})
+	}
+
+	if req.NamespaceLabelSelector != "" {
+		matching, err := s.watchNamespaces(sub)
+		if err != nil {
+			return nil, err
+		}
+		namespaces = append(namespaces, matching...)
+	}
+
+	var initial []*attachment
+	for _, namespace := range namespaces {
+		if _, ok := sub.attachments[namespace]; ok {
+			continue
+		}
+		attachments, err := s.attach(sub, sub.resources, namespace, req.ResourceVersion)
+		if err != nil {
+			return nil, err
+		}
+		sub.attachments[namespace] = attachments
+		initial = append(initial, attachments...)
+	}
+	return initial, nil
+}
+
+// attach attaches the event handler of sub to the informer of each of the
+// resources in namespace.
+func (s *server) attach(sub *subscription, resources []schema.GroupVersionResource, namespace, resourceVersion string) ([]*attachment, error) {
+	req := sub.req
+	var attachments []*attachment
+	for _, gvr := range resources {
+		spec := informerSpec{
+			gvr:           gvr,
+			namespace:     namespace,
+			labelSelector: req.LabelSelector,
+			fieldSelector: req.FieldSelector,
+			metadataOnly:  req.MetadataOnly,
+		}
+		s.Logger.Debug(fmt.Sprintf("GVR: %v", gvr))
+
+		a, err := s.attachResource(sub, spec, resourceVersion)
+		if err != nil {
+			for _, a := range attachments {
+				s.detach(a)
+			}
+			return nil, err
+		}
+		if a.registration.stopped != nil {
+			go s.reportExpired(sub, a)
+		}
+		attachments = append(attachments, a)
+	}
+	return attachments, nil
+}
+
+// attachResource attaches the event handler of sub to the informer of spec.
+// Metadata-only objects do not say what they are, so the kind of their
+// resource is looked up for the events.
+func (s *server) attachResource(sub *subscription, spec informerSpec, resourceVersion string) (*attachment, error) {
+	var kind schema.GroupVersionKind
+	if spec.metadataOnly {
+		var err error
+		if kind, err = s.resolver.kindFor(spec.gvr); err != nil {
+			return nil, fmt.Errorf("failed to find the kind of %s: %w", formatResources([]schema.GroupVersionResource{spec.gvr}), err)
+		}
+	}
+	a, err := s.attachInformer(sub.ctx, spec, s.eventHandler(sub, kind), resourceVersion, s.resyncPeriod(sub.req))
+	if err != nil {
+		return nil, err
+	}
+	a.kind = kind
+	return a, nil
+}
+
+// reportExpired sends an ERROR event asking the client of sub to watch
+// again should the resumed attachment a fall too far behind its history.
+func (s *server) reportExpired(sub *subscription, a *attachment) {
+	select {
+	case <-a.registration.stopped:
+	case <-sub.ctx.Done():
+		return
+	}
+	if !a.registration.expired() {
+		return
+	}
+	s.Logger.Info(fmt.Sprintf("Watch for %s fell too far behind %s", sub.key, a.key))
+	err := apierrors.NewResourceExpired(fmt.Sprintf("%s: %s fell too far behind", errResourceVersionExpired, formatResources([]schema.GroupVersionResource{a.gvr})))
+	sub.push(&queuedEvent{response: newErrorResponse(err)})
+}
+
+func (s *server) attachInformer(ctx context.Context, spec informerSpec, handler cache.ResourceEventHandler, resourceVersion string, resyncPeriod time.Duration) (*attachment, error) {
+	key := spec.key()
+	shared, err := s.informers.acquire(key, spec)
+	if err != nil {
+		return nil, err
+	}
+	// A handler added once the informer has listed still receives the list
+	if err := s.waitForList(ctx, shared, spec); err != nil {
+		s.informers.release(key)
+		return nil, err
+	}
+	registration, err := shared.subscribe(ctx, handler, resourceVersion, resyncPeriod)
+	if err != nil {
+		s.informers.release(key)
+		return nil, err
+	}
+	return &attachment{key: key, gvr: spec.gvr, shared: shared, registration: registration}, nil
+}
+
+// waitForList waits for the informer of spec to list, and for its history
+// to see the list. It fails as soon as the list is refused, and with
+// Unavailable when the list does not succeed within the sync timeout.
+func (s *server) waitForList(ctx context.Context, shared *sharedInformer, spec informerSpec) error {
+	timeoutCtx, cancel := context.WithTimeout(ctx, s.syncTimeout)
+	defer cancel()
+
+	err := wait.PollUntilContextCancel(timeoutCtx, syncPollInterval, true, func(context.Context) (bool, error) {
+		if shared.informer.HasSynced() && shared.historySynced() {
+			return true, nil
+		}
+		if err := shared.listError(); listRefused(err) {
+			return false, refusedListError(spec, err)
+		}
+		return false, nil
+	})
+	// Anything but running out of time is already the answer
+	if err == nil || ctx.Err() != nil || timeoutCtx.Err() == nil {
+		return err
+	}
+	message := fmt.Sprintf("timed out listing %s", spec.gvr.Resource)
+	if listErr := shared.listError(); listErr != nil {
+		message += ": " + listErr.Error()
+	}
+	return status.Error(codes.Unavailable, message)
+}
+
+// listRefused reports whether the API server rejected a list, which retrying
+// the same list does not fix.
+func listRefused(err error) bool {
+	return apierrors.IsForbidden(err) || apierrors.IsUnauthorized(err) || apierrors.IsBadRequest(err) || apierrors.IsInvalid(err)
+}
+
+// refusedListError converts a refused list of spec into a status. Only the
+// API server knows which field labels a resource supports, so a bad request
+// with a field selector is blamed on the selector.
+func refusedListError(spec informerSpec, err error) error {
+	if apierrors.IsBadRequest(err) && spec.fieldSelector != "" {
+		return invalidArgument("field_selector", err.Error())
+	}
+	return apiError(fmt.Errorf("cannot list %s: %w", spec.gvr.Resource, err))
+}
+
+// detach removes the event handler of a and releases its informer.
+func (s *server) detach(a *attachment) {
+	if err := a.registration.remove(); err != nil {
+		s.Logger.Error(fmt.Sprintf("Failed to remove event handler: %v", err))
+	}
+	s.informers.release(a.key)
+}
+
+func (s *server) detachAll(sub *subscription) {
+	sub.attachMu.Lock()
+	defer sub.attachMu.Unlock()
+
+	if sub.namespaces != nil {
+		s.detach(sub.namespaces)
+		sub.namespaces = nil
+	}
+	for _, a := range sub.apis {
+		s.detach(a)
+	}
+	sub.apis = nil
+	for namespace, attachments := range sub.attachments {
+		for _, a := range attachments {
+			s.detach(a)
+		}
+		delete(sub.attachments, namespace)
+	}
+}
+
+// watchNamespaces follows the namespaces matching the namespace selector of
+// sub and returns the ones matching now. The caller holds attachMu.
+func (s *server) watchNamespaces(sub *subscription) ([]string, error) {
+	spec := informerSpec{gvr: namespacesGVR, labelSelector: sub.req.NamespaceLabelSelector}
+	a, err := s.attachInformer(sub.ctx, spec, s.namespaceHandler(sub), "", 0)
+	if err != nil {
+		return nil, err
+	}
+	sub.namespaces = a
+
+	// Namespaces in the initial list are attached by the caller, so they can
+	// resume. Anything that changes after the list is left to the handler.
+	var namespaces []string
+	for _, obj := range a.shared.informer.GetStore().List() {
+		name, err := cache.MetaNamespaceKeyFunc(obj)
+		if err == nil {
+			namespaces = append(namespaces, name)
+		}
+	}
+	return namespaces, nil
+}
+
+// namespaceHandler attaches sub to namespaces that start matching its
+// namespace selector and detaches it from those that stop matching.
+func (s *server) namespaceHandler(sub *subscription) cache.ResourceEventHandler {
+	return cache.ResourceEventHandlerDetailedFuncs{
+		AddFunc: func(obj interface{}, isInInitialList bool) {
+			if isInInitialList {
+				return
+			}
+			if name, err := cache.MetaNamespaceKeyFunc(obj); err == nil {
+				s.addNamespace(sub, name)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			if name, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj); err == nil {
+				s.removeNamespace(sub, name)
+			}
+		},
+	}
+}
+
+func (s *server) addNamespace(sub *subscription, namespace string) {
+	sub.attachMu.Lock()
+	defer sub.attachMu.Unlock()
+
+	if _, ok := sub.attachments[namespace]; ok || sub.isStopped() {
+		return
+	}
+	s.Logger.Info(fmt.Sprintf("Namespace %s now matches the watch for %s", namespace, sub.key))
+	attachments, err := s.attach(sub, sub.resources, namespace, "")
+	if err != nil {
+		s.Logger.Error(fmt.Sprintf("Failed to watch namespace %s: %v", namespace, err))
+		resp := newErrorResponse(apierrors.NewInternalError(err))
+		sub.push(&queuedEvent{response: resp})
+		return
+	}
+	sub.attachments[namespace] = attachments
+}
+
+// removeNamespace detaches sub from namespace and sends a DELETE for every
+// object the client can no longer see, unless namespace was listed explicitly.
+// The DELETEs are pushed after attachMu is released, since a BLOCK queue can
+// wait for the client there; the namespace handler calls addNamespace and
+// removeNamespace in turn, so the namespace cannot be attached again before.
+func (s *server) removeNamespace(sub *subscription, namespace string) {
+	for _, listed := range sub.req.Namespaces {
+		if listed == namespace {
+			return
+		}
+	}
+
+	for _, event := range s.detachNamespace(sub, namespace) {
+		sub.push(event)
+	}
+}
+
+// detachNamespace detaches sub from namespace and returns a DELETE for every
+// object of it that sub matched.
+func (s *server) detachNamespace(sub *subscription, namespace string) []*queuedEvent {
+	sub.attachMu.Lock()
+	defer sub.attachMu.Unlock()
+
+	attachments, ok := sub.attachments[namespace]
+	if !ok || sub.isStopped() {
+		return nil
+	}
+	s.Logger.Info(fmt.Sprintf("Namespace %s no longer matches the watch for %s", namespace, sub.key))
+	delete(sub.attachments, namespace)
+	var deletes []*queuedEvent
+	for _, a := range attachments {
+		if err := a.registration.remove(); err != nil {
+			s.Logger.Error(fmt.Sprintf("Failed to remove event handler: %v", err))
+		}
+		for _, obj := range a.shared.informer.GetStore().List() {
+			event := &queuedEvent{eventType: api.EventType_DELETE, obj: obj, kind: a.kind}
+			if s.matchesFilter(sub, event) {
+				deletes = append(deletes, event)
+			}
+		}
+		s.informers.release(a.key)
+	}
+	return deletes
+}
+
+// eventHandler queues the informer events of sub, which are objects of kind
+// when it is set.
+func (s *server) eventHandler(sub *subscription, kind schema.GroupVersionKind) cache.ResourceEventHandler {
+	push := func(event *queuedEvent) {
+		event.kind = kind
+		if !s.matchesFilter(sub, event) {
+			return
+		}
+		if !sub.push(event) {
+			s.Logger.Error(fmt.Sprintf("Event queue is full, dropping %s event", event.eventType))
+		}
+	}
+	return cache.ResourceEventHandlerDetailedFuncs{
+		AddFunc: func(obj interface{}, isInInitialList bool) {
+			push(&queuedEvent{eventType: api.EventType_ADD, obj: obj, isInitialList: isInInitialList})
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			if resourceVersionChanged(oldObj, newObj) {
+				if sub.ignored.changed(oldObj, newObj) {
+					push(&queuedEvent{eventType: api.EventType_UPDATE, obj: newObj, oldObj: oldObj})
+				}
+			} else if sub.req.IncludeResyncs {
+				push(&queuedEvent{eventType: api.EventType_RESYNC, obj: newObj})
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			push(&queuedEvent{eventType: api.EventType_DELETE, obj: obj})
+		},
+	}
+}
+
+// resyncPeriod returns how often the objects of req are resynced, or zero
+// when req does not ask for RESYNC events.
+func (s *server) resyncPeriod(req *api.WatchRequest) time.Duration {
+	if !req.IncludeResyncs {
+		return 0
+	}
+	if req.ResyncPeriodSeconds > 0 {
+		return time.Duration(req.ResyncPeriodSeconds) * time.Second
+	}
+	return s.informers.resyncPeriod
+}
+
+// matchesFilter reports whether an object event passes the filter of sub.
+func (s *server) matchesFilter(sub *subscription, event *queuedEvent) bool {
+	if sub.filter == nil {
+		return true
+	}
+	matched, err := sub.filter.matches(event.eventType, event.obj, event.oldObj)
+	if err != nil {
+		s.Logger.Debug(fmt.Sprintf("Filter of %s failed on %s event: %v", sub.key, event.eventType, err))
+	}
+	return matched
+}
+
+// sendSynced queues a SYNCED event for sub once the handlers of its initial
+// attachments have received their initial lists.
+func (s *server) sendSynced(ctx context.Context, sub *subscription, initial []*attachment) {
+	err := wait.PollUntilContextCancel(ctx, syncPollInterval, true, func(context.Context) (bool, error) {
+		for _, a := range initial {
+			if !a.registration.hasSynced() {
+				return false, nil
+			}
+		}
+		return true, nil
+	})
+	if err != nil {
+		return
+	}
+	sub.push(&queuedEvent{response: newSyncedResponse(resumeVersion(initial))})
+}
+
+// resumeVersion returns the oldest resume version of the attachments. A
+// newer one would skip the events a slower informer has yet to deliver, so
+// resuming from it rather fails with 410 Expired for the attachments whose
+// history starts later.
+func resumeVersion(attachments []*attachment) string {
+	var oldest uint64
+	for _, a := range attachments {
+		rv, _ := strconv.ParseUint(a.shared.history.resumeVersion(), 10, 64)
+		if rv > 0 && (oldest == 0 || rv < oldest) {
+			oldest = rv
+		}
+	}
+	if oldest == 0 {
+		return ""
+	}
+	return strconv.FormatUint(oldest, 10)
+}
@@ -0,0 +1,203 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/fake"
+	clienttesting "k8s.io/client-go/testing"
+
+	"github.com/cmwylie19/watch-informer/api"
+)
+
+func newTestNamespace(name string, labels map[string]string) *unstructured.Unstructured {
+	namespace := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Namespace",
+		"metadata": map[string]interface{}{
+			"name":            name,
+			"resourceVersion": "1",
+		},
+	}}
+	namespace.SetLabels(labels)
+	return namespace
+}
+
+func TestWatchNamespaces(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := newTestWatchServer(ctrl, newTestPod("a", "nginx"), newTestPod("b", "redis"), newTestPod("c", "mysql"))
+	events, cancel, done := startTestWatch(ctrl, s, &api.WatchRequest{Version: "v1", Resource: "pod", Namespaces: []string{"a", "b"}})
+
+	waitForEventType(t, events, api.EventType_RESOLVED)
+	received := map[string]bool{}
+	for i := 0; i < 2; i++ {
+		event := waitForEventType(t, events, api.EventType_ADD)
+		received[event.Namespace+"/"+event.Name] = true
+	}
+	if !received["a/nginx"] || !received["b/redis"] {
+		t.Errorf("Expected the pods of namespaces a and b, got %v", received)
+	}
+	waitForEventType(t, events, api.EventType_SYNCED)
+	if s.informers.len() != 2 {
+		t.Errorf("Expected an informer per namespace, got %d", s.informers.len())
+	}
+
+	cancel()
+	<-done
+	waitForCondition(t, func() bool { return s.informers.len() == 0 })
+}
+
+func TestWatchNamespaceLabelSelector(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	payments := map[string]string{"team": "payments"}
+	s := newTestWatchServer(ctrl,
+		newTestNamespace("checkout", payments),
+		newTestNamespace("search", nil),
+		newTestPod("checkout", "nginx"),
+		newTestPod("search", "redis"),
+		newTestPod("billing", "mysql"),
+	)
+	events, cancel, done := startTestWatch(ctrl, s, &api.WatchRequest{Version: "v1", Resource: "pod", NamespaceLabelSelector: "team=payments"})
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	waitForEventType(t, events, api.EventType_RESOLVED)
+	if event := waitForEventType(t, events, api.EventType_ADD); event.Namespace != "checkout" {
+		t.Errorf("Expected the pod of namespace checkout, got %s/%s", event.Namespace, event.Name)
+	}
+	waitForEventType(t, events, api.EventType_SYNCED)
+
+	namespaces := s.dynamicClient.Resource(namespacesGVR)
+	if _, err := namespaces.Create(context.Background(), newTestNamespace("billing", payments), metav1.CreateOptions{}); err != nil {
+		t.Fatalf("Failed to create namespace: %v", err)
+	}
+	if event := waitForEventType(t, events, api.EventType_ADD); event.Namespace != "billing" || event.Name != "mysql" {
+		t.Errorf("Expected the pod of the new namespace, got %s/%s", event.Namespace, event.Name)
+	}
+
+	if err := namespaces.Delete(context.Background(), "checkout", metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("Failed to delete namespace: %v", err)
+	}
+	if event := waitForEventType(t, events, api.EventType_DELETE); event.Namespace != "checkout" || event.Name != "nginx" {
+		t.Errorf("Expected the pod of the removed namespace to be deleted, got %s/%s", event.Namespace, event.Name)
+	}
+	// One informer for the namespaces and one for billing
+	waitForCondition(t, func() bool { return s.informers.len() == 2 })
+}
+
+func TestWatchNamespaceLabelSelectorForbidden(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := newTestWatchServer(ctrl, newTestPod("checkout", "nginx"))
+	s.dynamicClient.(*fake.FakeDynamicClient).PrependReactor("list", "namespaces", func(clienttesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewForbidden(namespacesGVR.GroupResource(), "", errors.New("no RBAC rule"))
+	})
+	_, cancel, done := startTestWatch(ctrl, s, &api.WatchRequest{Version: "v1", Resource: "pod", NamespaceLabelSelector: "team=payments"})
+	defer cancel()
+
+	select {
+	case err := <-done:
+		if status.Code(err) != codes.PermissionDenied {
+			t.Errorf("Expected PermissionDenied, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for the watch to fail")
+	}
+	waitForCondition(t, func() bool { return s.informers.len() == 0 })
+}
+
+func TestResumeVersion(t *testing.T) {
+	attachmentAt := func(floor string) *attachment {
+		history := newEventHistory(historySize)
+		history.setFloor(floor)
+		return &attachment{shared: &sharedInformer{history: history}}
+	}
+
+	tests := []struct {
+		name        string
+		attachments []*attachment
+		expected    string
+	}{
+		{name: "No attachments", expected: ""},
+		{name: "One attachment", attachments: []*attachment{attachmentAt("12")}, expected: "12"},
+		{name: "Oldest of several", attachments: []*attachment{attachmentAt("12"), attachmentAt("10"), attachmentAt("11")}, expected: "10"},
+		{name: "Unknown floor", attachments: []*attachment{attachmentAt(""), attachmentAt("11")}, expected: "11"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if rv := resumeVersion(tc.attachments); rv != tc.expected {
+				t.Errorf("Expected %q, got %q", tc.expected, rv)
+			}
+		})
+	}
+}
+
+func TestWatchResyncs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := newTestWatchServer(ctrl, newTestPod("default", "nginx"))
+	s.informers.resyncPeriod = time.Second
+	resyncEvents, cancelResyncs, resyncsDone := startTestWatch(ctrl, s, &api.WatchRequest{Version: "v1", Resource: "pod", Namespace: "default", IncludeResyncs: true, ResyncPeriodSeconds: 1})
+	events, cancel, done := startTestWatch(ctrl, s, &api.WatchRequest{Version: "v1", Resource: "pod", Namespace: "default"})
+	defer func() {
+		cancelResyncs()
+		cancel()
+		<-resyncsDone
+		<-done
+	}()
+
+	for _, stream := range []<-chan *api.WatchResponse{resyncEvents, events} {
+		waitForEventType(t, stream, api.EventType_RESOLVED)
+		waitForEventType(t, stream, api.EventType_ADD)
+		waitForEventType(t, stream, api.EventType_SYNCED)
+	}
+	if event := waitForEventType(t, resyncEvents, api.EventType_RESYNC); event.Name != "nginx" || event.ResourceVersion != "1" {
+		t.Errorf("Expected the unchanged pod, got %s at %s", event.Name, event.ResourceVersion)
+	}
+
+	updated := newTestPod("default", "nginx")
+	updated.SetResourceVersion("2")
+	if _, err := s.dynamicClient.Resource(podsGVR).Namespace("default").Update(context.Background(), updated, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("Failed to update pod: %v", err)
+	}
+	if event := waitForEventType(t, events, api.EventType_UPDATE); event.ResourceVersion != "2" {
+		t.Errorf("Expected only the real update, got resourceVersion %s", event.ResourceVersion)
+	}
+}
+
+func TestEventHandlerDropsResyncs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := newTestWatchServer(ctrl)
+	sess := newSession(1, &api.WatchRequest{})
+	sub := &subscription{id: "a", req: &api.WatchRequest{}, sess: sess}
+
+	pod := newTestPod("default", "nginx")
+	s.eventHandler(sub, schema.GroupVersionKind{}).OnUpdate(pod, pod)
+	if sess.events.len() != 0 {
+		t.Errorf("Expected the resync to be dropped, got %d events", sess.events.len())
+	}
+
+	sub.req.IncludeResyncs = true
+	s.eventHandler(sub, schema.GroupVersionKind{}).OnUpdate(pod, pod)
+	assertEvents(t, []string{"RESYNC/nginx@1"}, drainQueue(t, sess.events))
+}